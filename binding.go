@@ -0,0 +1,267 @@
+package binder
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+)
+
+// ErrBindingClosed is returned when refreshing
+// a Binding which has been unbound.
+var ErrBindingClosed = errors.New("binding is closed")
+
+// BindOption is passed to Config.BindWith, and
+// used through functional parameters.
+type BindOption func(*Binding)
+
+// BindPrefix binds the instance to keys under
+// the specified prefix, with the prefix removed,
+// like binding to a Config created with Sub.
+func BindPrefix(prefix string) BindOption {
+	return func(b *Binding) {
+		b.prefix = joinKey(b.prefix, prefix)
+	}
+}
+
+// BindSilent re-binds the instance without calling
+// its Notify or NotifyChanges methods.
+func BindSilent() BindOption {
+	return func(b *Binding) {
+		b.silent = true
+	}
+}
+
+// BindNotifyFunc calls fn with the changes of every
+// re-bind which changed any field of the instance.
+func BindNotifyFunc(fn func(ChangeSet)) BindOption {
+	return func(b *Binding) {
+		b.fn = fn
+	}
+}
+
+// DependsOn re-binds and notifies the instance after
+// the instances of the specified bindings, when they
+// change in the same reload.
+func DependsOn(others ...*Binding) BindOption {
+	return func(b *Binding) {
+		b.deps = append(b.deps, others...)
+	}
+}
+
+// Binding is an instance bound to a Config, which
+// is re-bound when configuration changes, until it
+// is unbound.
+type Binding struct {
+	c      *Config
+	v      reflect.Value
+	prefix string
+	silent bool
+	fn     func(ChangeSet)
+	deps   []*Binding
+
+	// keys holds the normalized keys read by
+	// the latest bind, and closed is true once
+	// the instance is unbound.
+	keys   map[string]bool
+	closed bool
+}
+
+// BindWith binds configuration values to out, which
+// must be a pointer to a struct, and returns a handle
+// which can be used to unbind it. Options such as
+// BindPrefix apply to this binding only.
+func (c *Config) BindWith(out interface{}, opts ...BindOption) (*Binding, error) {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, errors.New("cannot bind to non-pointer or nil")
+	}
+
+	if v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("cannot bind to non-struct")
+	}
+
+	b := &Binding{c: c, v: v, prefix: c.prefix}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	c.m.Lock()
+	c.binders = append(c.binders, b)
+	c.m.Unlock()
+
+	_, _, errs := c.bind(b, false)
+	c.report(errs)
+
+	return b, nil
+}
+
+// Unbind stops re-binding the instance. The instance
+// is never written to after Unbind returns, although
+// a notification from a running reload may follow.
+func (b *Binding) Unbind() {
+	b.c.m.Lock()
+	defer b.c.m.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.closed = true
+	for i, other := range b.c.binders {
+		if other == b {
+			b.c.binders = append(b.c.binders[:i:i], b.c.binders[i+1:]...)
+			return
+		}
+	}
+}
+
+// Close unbinds the instance, and implements io.Closer.
+func (b *Binding) Close() error {
+	b.Unbind()
+
+	return nil
+}
+
+// Refresh re-binds the instance to the current
+// configuration values, without re-reading any parser,
+// and sends notifications if any field changed. It
+// returns a ReloadAbortedError if the instance fails to
+// prepare, or a RestartRequiredError if any field tagged
+// `reload:"false"` would have changed, along with the
+// errors of fields which couldn't be bound. Refresh
+// waits for any reload which is already running, and
+// must not be called from a notification.
+func (b *Binding) Refresh() (ChangeSet, error) {
+	b.c.m.Lock()
+	closed := b.closed
+	b.c.m.Unlock()
+
+	if closed {
+		return nil, ErrBindingClosed
+	}
+
+	var changes ChangeSet
+	var err error
+
+	if err := b.c.reloads.do(context.Background(), func() {
+		changes, err = b.refresh()
+	}); err != nil {
+		return nil, err
+	}
+
+	return changes, err
+}
+
+// refresh re-binds the instance, and commits it
+// if it implements Preparer.
+func (b *Binding) refresh() (ChangeSet, error) {
+	if b.c.ctx.Err() != nil {
+		return nil, ErrClosed
+	}
+
+	prepared, err := b.c.prepare([]*Binding{b})
+	if err != nil {
+		return nil, err
+	}
+
+	changes, restart, errs := b.c.bind(b, true)
+	if p, ok := prepared[b]; ok {
+		p.CommitReload()
+	}
+	if len(changes) > 0 {
+		b.notify(changes)
+	}
+
+	if len(restart) > 0 {
+		errs = append(errs, &RestartRequiredError{uniqueKeys(restart)})
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+// bindCopy binds the cached configuration values to a
+// copy of the instance, and returns a pointer to the copy
+// along with the changes from the instance.
+func (c *Config) bindCopy(b *Binding) (interface{}, ChangeSet) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if b.closed {
+		return nil, nil
+	}
+
+	next := reflect.New(b.v.Elem().Type())
+	next.Elem().Set(b.v.Elem())
+
+	elem := next.Elem()
+	for i := 0; i < elem.NumField(); i++ {
+		if f := elem.Field(i); f.CanSet() {
+			if _, ok := f.Interface().(*slog.LevelVar); ok {
+				f.Set(cloneField(f))
+			}
+		}
+	}
+
+	changes := c.decoder(b, true).bindStruct(elem)
+
+	return next.Interface(), changes
+}
+
+// orderBindings returns the bindings ordered so that
+// every binding follows the bindings it depends on,
+// and otherwise in the order they were bound.
+func orderBindings(binders []*Binding) []*Binding {
+	bound := make(map[*Binding]bool, len(binders))
+	for _, b := range binders {
+		bound[b] = true
+	}
+
+	ordered := make([]*Binding, 0, len(binders))
+	visited := make(map[*Binding]bool, len(binders))
+
+	var visit func(b *Binding)
+	visit = func(b *Binding) {
+		if visited[b] {
+			return
+		}
+		visited[b] = true
+
+		for _, dep := range b.deps {
+			if bound[dep] {
+				visit(dep)
+			}
+		}
+		ordered = append(ordered, b)
+	}
+
+	for _, b := range binders {
+		visit(b)
+	}
+
+	return ordered
+}
+
+// affected returns true if any of the changed keys,
+// normalized with the bind mode, is read by b.
+func (b *Binding) affected(changed map[string]bool) bool {
+	for k := range b.keys {
+		if changed[k] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notify sends the changes of a re-bind to the
+// notification func and to the instance.
+func (b *Binding) notify(changes ChangeSet) {
+	if b.fn != nil {
+		b.fn(changes)
+	}
+
+	if !b.silent {
+		notify(b.v.Interface(), changes)
+	}
+}
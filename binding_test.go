@@ -0,0 +1,307 @@
+package binder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BindWith(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{"host": "a"}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var out fakeCountingBinder
+	b, err := c.BindWith(&out)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+	assert.Equal(t, "a", out.Host)
+
+	p.m["host"] = "b"
+	c.apply("test")
+
+	assert.Equal(t, "b", out.Host)
+	assert.Equal(t, 1, out.notified)
+}
+
+func Test_BindWith_Invalid(t *testing.T) {
+	c := New()
+	defer c.Close()
+
+	_, err := c.BindWith(fakeCountingBinder{})
+	assert.Error(t, err)
+
+	n := 0
+	_, err = c.BindWith(&n)
+	assert.Error(t, err)
+}
+
+func Test_Binding_Unbind(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{"host": "a"}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var out fakeCountingBinder
+	b, _ := c.BindWith(&out)
+	assert.NoError(t, b.Close())
+	b.Unbind()
+	assert.Empty(t, c.binders)
+
+	p.m["host"] = "b"
+	c.apply("test")
+
+	assert.Equal(t, "a", out.Host)
+	assert.Equal(t, 0, out.notified)
+
+	_, err := b.Refresh()
+	assert.ErrorIs(t, err, ErrBindingClosed)
+}
+
+func Test_Binding_Refresh(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{"host": "a"}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var out fakeCountingBinder
+	b, _ := c.BindWith(&out)
+
+	out.Host = "local"
+	changes, err := b.Refresh()
+
+	assert.NoError(t, err)
+	assert.Equal(t, ChangeSet{{Path: "Host", Key: "host", Old: "local", New: "a"}}, changes)
+	assert.Equal(t, "a", out.Host)
+	assert.Equal(t, 1, out.notified)
+}
+
+func Test_Binding_Refresh_WaitsForReload(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{"host": "a"}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var out fakeCountingBinder
+	b, _ := c.BindWith(&out)
+	out.Host = "local"
+
+	// Hold the reloader, as a running reload does.
+	c.reloads.run <- struct{}{}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = b.Refresh()
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("refreshed during a reload")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	<-c.reloads.run
+	<-done
+
+	assert.Equal(t, "a", out.Host)
+}
+
+func Test_Binding_Refresh_Closed(t *testing.T) {
+	c := New()
+
+	var out fakeCountingBinder
+	b, _ := c.BindWith(&out)
+	c.Close()
+
+	_, err := b.Refresh()
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func Test_Binding_Refresh_ReloadFalse(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{"port": 8080}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var out fakeFrozenBinder
+	b, _ := c.BindWith(&out)
+
+	out.Port = 1
+	_, err := b.Refresh()
+
+	var restart *RestartRequiredError
+	assert.ErrorAs(t, err, &restart)
+	assert.Equal(t, []string{"port"}, restart.Keys)
+	assert.Equal(t, 1, out.Port)
+}
+
+func Test_BindPrefix(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"tenants.a.db.host": "a",
+		"tenants.b.db.host": "b",
+	}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var a, b fakeCountingBinder
+	_, _ = c.Sub("tenants").BindWith(&a, BindPrefix("a.db"))
+	_, _ = c.BindWith(&b, BindPrefix("tenants.b.db"))
+
+	assert.Equal(t, "a", a.Host)
+	assert.Equal(t, "b", b.Host)
+
+	p.m["tenants.a.db.host"] = "x"
+	changes, err := c.Reload(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, ChangeSet{{Path: "Host", Key: "tenants.a.db.host", Old: "a", New: "x"}}, changes)
+	assert.Equal(t, 1, a.notified)
+	assert.Equal(t, 0, b.notified)
+}
+
+func Test_BindSilent(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{"host": "a"}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var out fakeCountingBinder
+	var got ChangeSet
+	_, _ = c.BindWith(&out, BindSilent(), BindNotifyFunc(func(changes ChangeSet) {
+		got = changes
+	}))
+
+	p.m["host"] = "b"
+	c.apply("test")
+
+	assert.Equal(t, "b", out.Host)
+	assert.Equal(t, 0, out.notified)
+	assert.Equal(t, ChangeSet{{Path: "Host", Key: "host", Old: "a", New: "b"}}, got)
+}
+
+type fakePreparer struct {
+	Host      string `config:"host"`
+	err       error
+	next      *fakePreparer
+	committed int
+	log       *[]string
+}
+
+func (f *fakePreparer) PrepareReload(next any) error {
+	f.next = next.(*fakePreparer)
+	return f.err
+}
+
+func (f *fakePreparer) CommitReload() {
+	f.committed++
+}
+
+func (f *fakePreparer) Notify() {
+	if f.log != nil {
+		*f.log = append(*f.log, f.Host)
+	}
+}
+
+func Test_Preparer_Commit(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{"host": "a"}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var out fakePreparer
+	c.Bind(&out)
+
+	p.m["host"] = "b"
+	_, err := c.Reload(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "b", out.next.Host)
+	assert.Equal(t, "b", out.Host)
+	assert.Equal(t, 1, out.committed)
+}
+
+func Test_Preparer_Abort(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"a.host": "a",
+		"b.host": "a",
+	}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var first fakeCountingBinder
+	second := fakePreparer{err: errors.New("cannot connect")}
+	c.Sub("a").Bind(&first)
+	c.Sub("b").Bind(&second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := c.Changes(ctx)
+
+	p.m["a.host"] = "b"
+	p.m["b.host"] = "b"
+	_, err := c.Reload(context.Background())
+
+	var aborted *ReloadAbortedError
+	assert.ErrorAs(t, err, &aborted)
+	assert.ErrorIs(t, err, second.err)
+	assert.Equal(t, "a", first.Host)
+	assert.Equal(t, "a", second.Host)
+	assert.Equal(t, 0, second.committed)
+
+	host, _ := c.Values().Get("a.host")
+	assert.Equal(t, "a", host)
+
+	select {
+	case batch := <-ch:
+		t.Fatalf("unexpected batch: %v", batch)
+	default:
+	}
+
+	second.err = nil
+	c.refresh(c.sources, "test")
+
+	assert.Equal(t, "b", first.Host)
+	assert.Equal(t, "b", second.Host)
+	assert.Equal(t, 1, second.committed)
+}
+
+func Test_DependsOn(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"app.host": "a",
+		"db.host":  "a",
+	}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var log []string
+	app := fakePreparer{log: &log}
+	db := fakePreparer{log: &log}
+
+	b, _ := c.BindWith(&db, BindPrefix("db"))
+	_, _ = c.BindWith(&app, BindPrefix("app"), DependsOn(b))
+
+	// Bound last, but notified first.
+	c.m.Lock()
+	c.binders[0], c.binders[1] = c.binders[1], c.binders[0]
+	c.m.Unlock()
+
+	p.m["app.host"] = "app"
+	p.m["db.host"] = "db"
+	c.apply("test")
+
+	assert.Equal(t, []string{"db", "app"}, log)
+}
+
+func Test_OrderBindings(t *testing.T) {
+	a := &Binding{}
+	b := &Binding{}
+	c := &Binding{deps: []*Binding{a, b}}
+	d := &Binding{deps: []*Binding{c, {}}}
+
+	assert.Equal(t, []*Binding{a, b, c, d}, orderBindings([]*Binding{d, a, b, c}))
+
+	// Cycles are broken at the binding visited first.
+	x := &Binding{}
+	y := &Binding{deps: []*Binding{x}}
+	x.deps = []*Binding{y}
+
+	assert.Equal(t, []*Binding{y, x}, orderBindings([]*Binding{x, y}))
+}
@@ -0,0 +1,243 @@
+package binder
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Change describes a bound struct field whose
+// value changed when re-binding.
+type Change struct {
+	// Path is the name of the struct field.
+	Path string
+
+	// Key is the configuration key bound to the field.
+	Key string
+
+	Old interface{}
+	New interface{}
+}
+
+// ChangeSet is a collection of changes from
+// a single re-bind.
+type ChangeSet []Change
+
+// Has returns true if the field with the
+// specified path changed.
+func (cs ChangeSet) Has(path string) bool {
+	for _, c := range cs {
+		if c.Path == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ChangeEvent describes a configuration key whose
+// value changed when reloading. Old is nil if the key
+// was added, and New is nil if the key was removed.
+// A key whose value became stale, or is no longer
+// stale, is also reported as changed.
+type ChangeEvent struct {
+	Key string
+	Old *Value
+	New *Value
+
+	// Source is the name of the parser which
+	// supplied the new value.
+	Source string
+}
+
+type subscription struct {
+	prefix  string
+	pattern string
+	fn      func(ev ChangeEvent)
+}
+
+// OnChange registers fn to be called after each reload,
+// once for every changed key matching pattern. The pattern
+// is either a key or a glob such as `db.*`, using the syntax
+// of path.Match. The returned func removes the registration.
+func (c *Config) OnChange(pattern string, fn func(ev ChangeEvent)) func() {
+	s := &subscription{c.prefix, pattern, fn}
+
+	c.current()
+
+	c.m.Lock()
+	c.subs = append(c.subs, s)
+	c.m.Unlock()
+
+	return func() {
+		c.m.Lock()
+		defer c.m.Unlock()
+
+		for i, other := range c.subs {
+			if other == s {
+				c.subs = append(c.subs[:i:i], c.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (s *subscription) match(key string, mode BindMode) bool {
+	pattern := s.pattern
+	if mode.fuzzy().has(ModeIgnoreCase) {
+		pattern = strings.ToLower(pattern)
+		key = strings.ToLower(key)
+	}
+
+	ok, _ := path.Match(pattern, key)
+	return ok
+}
+
+func (s *subscription) publish(old *Values, next *Values, mode BindMode) {
+	if s.prefix != "" {
+		old = old.sub(s.prefix, mode)
+		next = next.sub(s.prefix, mode)
+	}
+
+	for _, ev := range diffValues(old, next) {
+		if s.match(ev.Key, mode) {
+			s.fn(ev)
+		}
+	}
+}
+
+// diffValues returns an event for every key which
+// was added, removed or modified, ordered by key.
+func diffValues(old *Values, next *Values) []ChangeEvent {
+	var events []ChangeEvent
+
+	for k, o := range old.m {
+		n := next.m[k]
+		if !o.equal(n) {
+			events = append(events, ChangeEvent{k, o, n, n.Source()})
+		}
+	}
+
+	for k, n := range next.m {
+		if _, ok := old.m[k]; !ok {
+			events = append(events, ChangeEvent{k, nil, n, n.Source()})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Key < events[j].Key
+	})
+
+	return events
+}
+
+// ChangeBatch holds every key which was added,
+// removed or modified by a single reload.
+type ChangeBatch struct {
+	// Revision increases monotonically with
+	// every reload, starting at 1.
+	Revision uint64
+	Time     time.Time
+
+	// Trigger describes what caused the reload,
+	// such as the path of a changed file.
+	Trigger string
+
+	Added    []ChangeEvent
+	Removed  []ChangeEvent
+	Modified []ChangeEvent
+}
+
+type stream struct {
+	prefix string
+	ctx    context.Context
+	done   <-chan struct{}
+	ch     chan ChangeBatch
+	m      sync.Mutex
+	closed bool
+}
+
+// Changes returns a channel which receives one
+// ChangeBatch per reload, until ctx is done or the
+// Config is closed and the channel is closed. A reload
+// waits for the batch to be received when the channel
+// buffer is full, so the channel should be drained
+// promptly.
+func (c *Config) Changes(ctx context.Context) <-chan ChangeBatch {
+	s := &stream{
+		prefix: c.prefix,
+		ctx:    ctx,
+		done:   c.ctx.Done(),
+		ch:     make(chan ChangeBatch, 16),
+	}
+
+	c.current()
+
+	c.m.Lock()
+	c.streams = append(c.streams, s)
+	c.m.Unlock()
+
+	stop := func() {
+		c.m.Lock()
+		for i, other := range c.streams {
+			if other == s {
+				c.streams = append(c.streams[:i:i], c.streams[i+1:]...)
+				break
+			}
+		}
+		c.m.Unlock()
+
+		s.m.Lock()
+		defer s.m.Unlock()
+
+		s.closed = true
+		close(s.ch)
+	}
+
+	started := c.spawn(func() {
+		select {
+		case <-ctx.Done():
+		case <-c.ctx.Done():
+		}
+		stop()
+	})
+	if !started {
+		stop()
+	}
+
+	return s.ch
+}
+
+func (s *stream) publish(old *Values, next *Values, mode BindMode, batch ChangeBatch) {
+	if s.prefix != "" {
+		old = old.sub(s.prefix, mode)
+		next = next.sub(s.prefix, mode)
+	}
+
+	for _, ev := range diffValues(old, next) {
+		switch {
+		case ev.Old == nil:
+			batch.Added = append(batch.Added, ev)
+		case ev.New == nil:
+			batch.Removed = append(batch.Removed, ev)
+		default:
+			batch.Modified = append(batch.Modified, ev)
+		}
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.ch <- batch:
+	case <-s.ctx.Done():
+	case <-s.done:
+	}
+}
@@ -0,0 +1,139 @@
+package binder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ChangeSet_Has(t *testing.T) {
+	cs := ChangeSet{
+		{Path: "First", Key: "first", Old: "a", New: "b"},
+	}
+
+	assert.True(t, cs.Has("First"))
+	assert.False(t, cs.Has("first"))
+}
+
+func Test_OnChange(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"db.host":    "a",
+		"db.port":    "5432",
+		"rate_limit": "10",
+	}}
+	c := New(WithParser(p))
+
+	var events []ChangeEvent
+	c.OnChange("db.*", func(ev ChangeEvent) {
+		events = append(events, ev)
+	})
+
+	p.m["db.host"] = "b"
+	delete(p.m, "db.port")
+	p.m["db.user"] = "admin"
+	p.m["rate_limit"] = "20"
+	c.apply("test")
+
+	assert.Len(t, events, 3)
+
+	assert.Equal(t, "db.host", events[0].Key)
+	old, _ := events[0].Old.String()
+	next, _ := events[0].New.String()
+	assert.Equal(t, "a", old)
+	assert.Equal(t, "b", next)
+	assert.Equal(t, "*binder.fakeMultiParser", events[0].Source)
+
+	assert.Equal(t, "db.port", events[1].Key)
+	assert.Nil(t, events[1].New)
+
+	assert.Equal(t, "db.user", events[2].Key)
+	assert.Nil(t, events[2].Old)
+}
+
+func Test_OnChange_Unsubscribe(t *testing.T) {
+	p := &fakeRebindParser{"value1"}
+	c := New(WithParser(p))
+
+	calls := 0
+	unsubscribe := c.OnChange("binder_key", func(ChangeEvent) {
+		calls++
+	})
+
+	p.value = "value2"
+	c.apply("test")
+	assert.Equal(t, 1, calls)
+
+	unsubscribe()
+
+	p.value = "value3"
+	c.apply("test")
+	assert.Equal(t, 1, calls)
+}
+
+func Test_OnChange_Sub(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"search.rate_limit": "10",
+	}}
+	c := New(WithParser(p))
+
+	var keys []string
+	c.Sub("search").OnChange("rate_limit", func(ev ChangeEvent) {
+		keys = append(keys, ev.Key)
+	})
+
+	p.m["search.rate_limit"] = "20"
+	c.apply("test")
+
+	assert.Equal(t, []string{"rate_limit"}, keys)
+}
+
+func Test_Changes(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"a": "1",
+		"b": "2",
+	}}
+	c := New(WithParser(p))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := c.Changes(ctx)
+
+	p.m["a"] = "10"
+	delete(p.m, "b")
+	p.m["c"] = "3"
+	c.apply("/etc/values.conf")
+
+	batch := <-ch
+	assert.Equal(t, uint64(1), batch.Revision)
+	assert.Equal(t, "/etc/values.conf", batch.Trigger)
+	assert.False(t, batch.Time.IsZero())
+	assert.Len(t, batch.Added, 1)
+	assert.Equal(t, "c", batch.Added[0].Key)
+	assert.Len(t, batch.Removed, 1)
+	assert.Equal(t, "b", batch.Removed[0].Key)
+	assert.Len(t, batch.Modified, 1)
+	assert.Equal(t, "a", batch.Modified[0].Key)
+
+	c.apply("test")
+
+	batch = <-ch
+	assert.Equal(t, uint64(2), batch.Revision)
+	assert.Empty(t, batch.Added)
+	assert.Empty(t, batch.Removed)
+	assert.Empty(t, batch.Modified)
+}
+
+func Test_Changes_Cancel(t *testing.T) {
+	c := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := c.Changes(ctx)
+	cancel()
+
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	c.apply("test")
+}
@@ -1,15 +1,15 @@
 package binder
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
 	"sync"
-
-	"github.com/fsnotify/fsnotify"
+	"time"
 )
 
-const configStructTagName string = "config"
-
 // Parser is an interface which defines
 // the minimum requirement to implement
 // a custom configuration parser.
@@ -17,17 +17,120 @@ type Parser interface {
 	Parse() (map[string]interface{}, error)
 }
 
+// Watchable can be implemented by a Parser
+// which reads files, to have the files watched
+// for changes when using AutoWatch or WithAutoWatch.
+type Watchable interface {
+	WatchPaths() []string
+}
+
+// Notifier can be implemented by a bound
+// instance to get notified when a re-bind
+// changed any of its fields.
+type Notifier interface {
+	Notify()
+}
+
+// ChangeNotifier can be implemented by a bound
+// instance to get notified with every field that
+// changed when re-binding.
+type ChangeNotifier interface {
+	NotifyChanges(ChangeSet)
+}
+
+// Preparer can be implemented by a bound instance
+// to validate new configuration values before any
+// instance is re-bound. PrepareReload is passed a
+// pointer to a copy of the instance with the new
+// values bound, and aborts the whole reload by
+// returning an error. CommitReload is called once
+// the instance has been re-bound, and is not called
+// when the reload is aborted, in which case the next
+// PrepareReload replaces anything prepared.
+type Preparer interface {
+	PrepareReload(next any) error
+	CommitReload()
+}
+
 // Config is the configuration handler,
 // which can be read from or bound to
 // a custom type.
 type Config struct {
-	parsers []Parser
-	mask    BindMode
-	binders []reflect.Value
-	cache   *Values
-	errch   chan error
-	watch   *fsnotify.Watcher
-	m       sync.Mutex
+	*core
+	prefix string
+
+	// scoped is true for a Config created with Sub,
+	// which doesn't own the shared state.
+	scoped bool
+}
+
+// core is the state shared between a Config
+// and every scoped Config created with Sub.
+type core struct {
+	sources   []*source
+	mask      BindMode
+	tags      []string
+	binders   []*Binding
+	vars      []liveVar
+	snapshots []snapshot
+	subs      []*subscription
+	streams   []*stream
+	cache     *Values
+	errch     chan error
+	watch     *fileWatcher
+	poll      *pollWatcher
+	watches   []watchRequest
+	triggers  []Trigger
+	ctx       context.Context
+	cancel    context.CancelFunc
+	reloads   *reloader
+	m         sync.Mutex
+
+	// wg tracks every goroutine Close waits for, and
+	// errm guards sending on errch against Close.
+	wg        sync.WaitGroup
+	closing   sync.Once
+	errm      sync.RWMutex
+	errClosed bool
+
+	revision        uint64
+	pollInterval    time.Duration
+	refreshInterval time.Duration
+	maxStale        time.Duration
+
+	warnCollisions bool
+	autoWatch      bool
+	ready          bool
+
+	// noEvents is true when file system events are
+	// unavailable, and every path is polled.
+	noEvents bool
+}
+
+// source holds the settings of a Parser
+// added with ParserOption(s), and the values
+// from its latest parse.
+type source struct {
+	p         Parser
+	autoWatch bool
+	refresh   time.Duration
+	maxStale  time.Duration
+
+	raw    map[string]interface{}
+	parsed bool
+
+	// good is the time of the last successful parse,
+	// and stale is true while raw is kept from it
+	// because the parser fails.
+	good  time.Time
+	stale bool
+}
+
+// watchRequest is a watch added through WithWatch,
+// which is started once every Option is applied.
+type watchRequest struct {
+	path string
+	opts []WatchOption
 }
 
 // New is the configuration constructor,
@@ -35,24 +138,138 @@ type Config struct {
 // to support a plethora of backing
 // configuration parsers.
 func New(opts ...Option) *Config {
-	c := &Config{}
+	c := &Config{core: &core{}}
 	c.mask = DefaultBindMode
+	c.tags = []string{configStructTagName}
 	c.errch = make(chan error, 1)
+	c.reloads = newReloader(c.apply)
+	c.ctx, c.cancel = context.WithCancel(context.Background())
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	for _, w := range c.watches {
+		c.Watch(w.path, w.opts...)
+	}
+
+	for _, s := range c.sources {
+		c.watchSource(s)
+	}
+	c.startRefresh(c.sources)
+
+	for _, t := range c.triggers {
+		c.start(t)
+	}
+
+	c.ready = true
+
 	return c
 }
 
-// Close cleans up channels and file watches.
+// start starts a Trigger, which reloads the
+// configuration until the Config is closed.
+func (c *Config) start(t Trigger) {
+	if c.ctx.Err() != nil {
+		return
+	}
+
+	if r, ok := t.(errorReporter); ok {
+		r.reportErrors(c.errs)
+	}
+
+	if r, ok := t.(tracker); ok {
+		r.track(func(fn func()) { c.spawn(fn) })
+	}
+
+	if err := t.Start(c.ctx, c.reloads.request); err != nil {
+		c.errs(err)
+	}
+}
+
+// spawn runs fn in a goroutine which Close waits for,
+// and returns false without running fn if the Config
+// is closed.
+func (c *Config) spawn(fn func()) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.ctx.Err() != nil {
+		return false
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		fn()
+	}()
+
+	return true
+}
+
+// ErrClosed is returned when reloading a Config
+// which has been closed.
+var ErrClosed = errors.New("config is closed")
+
+// Close stops every watch, trigger and refresh, waits
+// for a running reload and for their goroutines to exit,
+// and closes the Errors channel. No bound instance is
+// re-bound or notified after Close returns, and calling
+// Close again has no effect. Close must not be called
+// from a notification, since it waits for the reload.
+// Closing a scoped Config created with Sub has no
+// effect, since its parent owns the resources.
 func (c *Config) Close() {
-	if c.watch != nil {
-		_ = c.watch.Close()
+	if c.scoped {
+		return
+	}
+
+	c.closing.Do(func() {
+		c.m.Lock()
+		c.cancel()
+		c.m.Unlock()
+
+		c.reloads.stop()
+		c.wg.Wait()
+
+		if c.watch != nil {
+			_ = c.watch.Close()
+		}
+
+		c.errm.Lock()
+		defer c.errm.Unlock()
+
+		c.errClosed = true
+		close(c.errch)
+	})
+}
+
+// Start ties the lifetime of the Config to ctx, and
+// closes it when ctx is done. Watches, triggers and
+// refreshes run from the moment they are added.
+func (c *Config) Start(ctx context.Context) {
+	c.spawn(func() {
+		select {
+		case <-ctx.Done():
+			// Close waits for this goroutine to exit.
+			go c.Close()
+		case <-c.ctx.Done():
+		}
+	})
+}
+
+// Run blocks until ctx is done or the Config is
+// closed, and returns the error of ctx once the Config
+// is closed and every goroutine has exited.
+func (c *Config) Run(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+	case <-c.ctx.Done():
 	}
 
-	close(c.errch)
+	c.Close()
+
+	return ctx.Err()
 }
 
 // Errors returns a `chan error` where
@@ -62,7 +279,14 @@ func (c *Config) Errors() <-chan error {
 	return c.errch
 }
 
-func (c *Config) errs(err error) {
+func (c *core) errs(err error) {
+	c.errm.RLock()
+	defer c.errm.RUnlock()
+
+	if c.errClosed {
+		return
+	}
+
 	select {
 	case c.errch <- err:
 		return
@@ -73,232 +297,543 @@ func (c *Config) errs(err error) {
 
 // Use appends a backing Parser to the
 // configuration handler.
-func (c *Config) Use(p Parser) {
-	c.parsers = append(c.parsers, p)
+func (c *Config) Use(p Parser, opts ...ParserOption) {
+	s := &source{p: p}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	c.m.Lock()
+	c.sources = append(c.sources, s)
+	ready := c.ready
+	c.m.Unlock()
+
+	if ready {
+		c.watchSource(s)
+		c.startRefresh([]*source{s})
+	}
+}
+
+// watchSource watches the paths of a Watchable
+// parser, when AutoWatch or WithAutoWatch is used.
+func (c *Config) watchSource(s *source) {
+	w, ok := s.p.(Watchable)
+	if !ok || !(s.autoWatch || c.autoWatch) {
+		return
+	}
+
+	for _, path := range w.WatchPaths() {
+		c.Watch(path)
+	}
 }
 
 // Watch adds a file or directory watch to the
 // specified path, which will trigger a re-bind
-// for any bound configuration.
-func (c *Config) Watch(path string) {
-	if c.watch == nil {
-		c.watch = newFileWatcher(c.apply, c.errs)
+// for any bound configuration. A file is watched
+// through its parent directory, so that the watch
+// survives the file being replaced, and a path which
+// doesn't exist yet is watched until it appears.
+//
+// The path is polled instead when passing
+// WatchPolling, or when file system events are
+// unavailable, at the interval set with
+// WithPollInterval.
+func (c *Config) Watch(path string, opts ...WatchOption) {
+	if c.ctx.Err() != nil {
+		return
+	}
+
+	var o watchOptions
+	for _, opt := range opts {
+		opt(&o)
 	}
 
-	if err := c.watch.Add(path); err != nil {
+	if err := c.watcher(o.poll).Add(path, opts...); err != nil {
 		c.errs(err)
 	}
 }
 
+// pathWatcher is implemented by fileWatcher
+// and pollWatcher.
+type pathWatcher interface {
+	Add(path string, opts ...WatchOption) error
+}
+
+// watcher returns the file watcher, or the poller if
+// poll is true, and starts it on first use. It falls
+// back to polling when file system events are
+// unavailable.
+func (c *Config) watcher(poll bool) pathWatcher {
+	if !poll && c.watch == nil && !c.noEvents {
+		fw, err := newFileWatcher(c.errs)
+		if err != nil {
+			c.errs(err)
+			c.noEvents = true
+		} else {
+			c.watch = fw
+			c.start(fw)
+		}
+	}
+
+	if !poll && c.watch != nil {
+		return c.watch
+	}
+
+	if c.poll == nil {
+		interval := c.pollInterval
+		if interval == 0 {
+			interval = defaultPollInterval
+		}
+		c.poll = newPollWatcher(interval, c.errs)
+		c.start(c.poll)
+	}
+
+	return c.poll
+}
+
 // Values iterates through all specified
 // backing parsers, and retrieves configuration
 // values from all of them.
 func (c *Config) Values() *Values {
-	if c.cache == nil {
-		c.build()
+	values := c.current()
+	if c.prefix != "" {
+		return values.sub(c.prefix, c.mask)
 	}
 
+	return values
+}
+
+// current returns the cached configuration
+// values, building them on first use.
+func (c *Config) current() *Values {
+	c.m.Lock()
+	values := c.cache
+	c.m.Unlock()
+
+	if values != nil {
+		return values
+	}
+
+	c.report(c.build())
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
 	return c.cache
 }
 
-func (c *Config) build() {
-	m := make(map[string]*Value)
+// Sub returns a scoped Config, whose Values and
+// Bind operate on keys under the specified prefix
+// with the prefix removed. The scoped Config shares
+// parsers, watches and reloads with its parent, so
+// instances bound to it are re-bound when the parent
+// reloads.
+func (c *Config) Sub(prefix string) *Config {
+	return &Config{c.core, joinKey(c.prefix, prefix), true}
+}
+
+func (c *Config) build() []error {
+	c.m.Lock()
+	sources := append([]*source(nil), c.sources...)
+	c.m.Unlock()
+
+	_, errs := c.parse(sources)
+	c.merge()
+
+	return errs
+}
+
+// report sends every error to the error channel.
+func (c *Config) report(errs []error) {
+	for _, err := range errs {
+		c.errs(err)
+	}
+}
 
-	for _, p := range c.parsers {
-		raw, err := p.Parse()
+// parse re-reads the specified sources, and returns
+// true if the values of any source changed, along
+// with the errors of failed parsers. A failed parser
+// keeps the values of its last successful parse, until
+// they are older than the maximum staleness.
+func (c *Config) parse(sources []*source) (bool, []error) {
+	changed := false
+	var errs []error
+
+	for _, s := range sources {
+		raw, err := s.p.Parse()
+		now := time.Now()
+
+		c.m.Lock()
+		stale := false
 		if err != nil {
-			c.errs(err)
+			if c.servable(s, now) {
+				raw, stale = s.raw, true
+				err = &StaleSourceError{parserName(s.p), s.good, err}
+			}
+			errs = append(errs, err)
+		} else {
+			s.good = now
+		}
+
+		if !s.parsed || stale != s.stale || !reflect.DeepEqual(raw, s.raw) {
+			s.raw = raw
+			s.stale = stale
+			s.parsed = true
+			changed = true
+		}
+		c.m.Unlock()
+	}
+
+	return changed, errs
+}
+
+// servable returns true if the values of the last
+// successful parse of a source may still be used.
+func (c *Config) servable(s *source, now time.Time) bool {
+	if s.good.IsZero() {
+		return false
+	}
+
+	d := s.maxStale
+	if d == 0 {
+		d = c.maxStale
+	}
+
+	return d <= 0 || now.Sub(s.good) <= d
+}
+
+// merge combines the latest values of every source
+// into the cached configuration values.
+func (c *Config) merge() {
+	m := make(map[string]*Value)
+	pos := make(map[string]int)
+	n := 0
+
+	c.m.Lock()
+	sources := append([]*source(nil), c.sources...)
+	for _, s := range sources {
+		src := parserName(s.p)
+
+		keys := make([]string, 0, len(s.raw))
+		for k := range s.raw {
+			keys = append(keys, k)
 		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			m[k] = &Value{s.raw[k], src, s.stale}
+			pos[k] = n
+			n++
+		}
+	}
+	c.m.Unlock()
 
-		for k, v := range raw {
-			m[k] = &Value{v}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return pos[keys[i]] < pos[keys[j]]
+	})
+
+	values := newValues(m, keys)
+	mode := c.mask.fuzzy()
+	if mode != 0 {
+		values.index(mode)
+	}
+
+	if c.warnCollisions && mode != 0 {
+		for _, group := range values.collisions(mode) {
+			c.errs(&KeyCollisionError{group})
 		}
 	}
 
 	c.m.Lock()
 	defer c.m.Unlock()
 
-	c.cache = &Values{m}
+	c.cache = values
+}
+
+// parserName returns the name of a parser, used
+// as source of the values it supplies.
+func parserName(p Parser) string {
+	if s, ok := p.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	return fmt.Sprintf("%T", p)
 }
 
 // Bind takes one or more pointers to a custom type,
-// which configuration values will be bound to.
+// which configuration values will be bound to. Use
+// BindWith to get a handle of the binding.
 func (c *Config) Bind(outs ...interface{}) {
 	for _, out := range outs {
-		v := reflect.ValueOf(out)
-		if v.Kind() != reflect.Ptr || v.IsNil() {
-			c.errs(errors.New("cannot bind to non-pointer or nil"))
-			continue
+		if _, err := c.BindWith(out); err != nil {
+			c.errs(err)
 		}
-
-		c.binders = append(c.binders, v)
-		c.bind(v)
 	}
 }
 
-func (c *Config) bind(v reflect.Value) {
-	if c.cache == nil {
-		c.build()
-	}
+// bind binds the cached configuration values to a bound
+// instance. When reload is true, fields tagged
+// `reload:"false"` keep their values, and the keys of
+// such fields which would have changed are returned.
+// An unbound instance is left untouched. It returns the
+// errors of fields which couldn't be bound, rather than
+// reporting them.
+func (c *Config) bind(b *Binding, reload bool) (ChangeSet, []string, []error) {
+	c.current()
 
 	c.m.Lock()
 	defer c.m.Unlock()
 
-	elem := v.Elem()
-	t := elem.Type()
-	changed := false
-	for i := 0; i < t.NumField(); i++ {
-		tag, ok := t.Field(i).Tag.Lookup(configStructTagName)
-		if !ok || tag == "" || tag == "-" {
-			continue
-		}
-
-		changed = c.bindValue(elem.Field(i), tag)
+	if b.closed {
+		return nil, nil, nil
 	}
 
-	method := v.MethodByName("Notify")
-	n := reflect.Value{}
-	if changed && method != n {
-		method.Call([]reflect.Value{})
+	d := c.decoder(b, reload)
+	changes := d.bindStruct(b.v.Elem())
+
+	mode := c.mask.fuzzy()
+	b.keys = make(map[string]bool, len(d.keys))
+	for _, k := range d.keys {
+		b.keys[mode.normalize(k)] = true
 	}
+
+	return changes, d.restart, d.errs
 }
 
-func (c *Config) bindValue(elem reflect.Value, tag string) bool {
-	switch elem.Kind() {
-	case reflect.String:
-		return c.bindString(elem, tag)
-	case reflect.Array, reflect.Slice:
-		return c.bindStringArray(elem, tag)
-	case reflect.Int:
-		return c.bindInt(elem, tag)
-	case reflect.Float32:
-		return c.bindFloat32(elem, tag)
-	case reflect.Float64:
-		return c.bindFloat64(elem, tag)
-	case reflect.Bool:
-		return c.bindBool(elem, tag)
+// decoder returns a decoder of the cached configuration
+// values for a bound instance. The caller must hold c.m.
+func (c *Config) decoder(b *Binding, reload bool) *decoder {
+	values := c.cache
+	if b.prefix != "" {
+		values = values.sub(b.prefix, c.mask)
 	}
 
-	return false
+	d := newDecoder(values, c.mask, c.tags)
+	d.prefix = b.prefix
+	d.reload = reload
+
+	return d
 }
 
-func (c *Config) bindString(elem reflect.Value, tag string) bool {
-	value, ok := c.cache.getString(tag, c.mask)
-	if ok {
-		cur := elem.String()
-		elem.SetString(value)
-		return cur != "" && cur != value
-	}
+// apply re-builds configuration values and re-binds every
+// bound instance. The trigger describes what caused the
+// reload, such as the path of a changed file.
+func (c *Config) apply(trigger string) {
+	old := c.current()
+	c.report(c.build())
 
-	return false
+	_, errs := c.rebind(old, trigger)
+	c.report(errs)
 }
 
-func (c *Config) bindStringArray(elem reflect.Value, tag string) bool {
-	value, ok := c.cache.getStrings(tag, c.mask)
-	if ok {
-		cur := elem.Interface().([]string)
-		elem.Set(reflect.ValueOf(value))
-		return cur != nil && isSliceEqual(cur, value)
+// Reload synchronously re-reads every parser and re-binds
+// every bound instance, after any reload which is already
+// running. It returns the changes of all bound instances,
+// and the errors of failed parsers instead of reporting
+// them to the Errors channel, including a RestartRequiredError.
+// The context only bounds the wait for a running reload.
+func (c *Config) Reload(ctx context.Context) (ChangeSet, error) {
+	var changes ChangeSet
+	var errs []error
+
+	err := c.reloads.do(ctx, func() {
+		old := c.current()
+		errs = c.build()
+
+		var rerrs []error
+		changes, rerrs = c.rebind(old, TriggerManual)
+		errs = append(errs, rerrs...)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return false
+	return changes, errors.Join(errs...)
 }
 
-func (c *Config) bindInt(elem reflect.Value, tag string) bool {
-	value, ok := c.cache.getInt(tag, c.mask)
-	if ok {
-		cur := elem.Int()
-		elem.SetInt(int64(value))
-		return cur != 0 && cur != int64(value)
+// refresh re-reads the specified sources, and re-binds
+// every bound instance only if their values changed.
+func (c *Config) refresh(sources []*source, trigger string) {
+	old := c.current()
+	changed, errs := c.parse(sources)
+	c.report(errs)
+	if !changed {
+		return
 	}
 
-	return false
+	c.merge()
+
+	_, errs = c.rebind(old, trigger)
+	c.report(errs)
 }
 
-func (c *Config) bindFloat32(elem reflect.Value, tag string) bool {
-	value, ok := c.cache.getFloat(tag, c.mask)
-	if ok {
-		cur := elem.Float()
-		elem.SetFloat(value)
-		return cur != 0 && cur != float64(value)
+// rebind re-binds every bound instance which reads any
+// key that differs from the old values, publishes the
+// changes, and returns the changes of all instances.
+// Instances are re-bound in dependency order, after every
+// Preparer has accepted the new values. It returns a
+// ReloadAbortedError and restores the old values if any
+// Preparer fails, or a RestartRequiredError if any field
+// tagged `reload:"false"` would have changed, along with
+// the errors of fields which couldn't be bound.
+func (c *Config) rebind(old *Values, trigger string) (ChangeSet, []error) {
+	affected := c.affected(old)
+
+	prepared, err := c.prepare(affected)
+	if err != nil {
+		c.abort(old)
+		return nil, []error{err}
 	}
 
-	return false
-}
+	restart, errs, revision := c.commit()
+
+	var all ChangeSet
+	for _, b := range affected {
+		changes, keys, berrs := c.bind(b, true)
+		restart = append(restart, keys...)
+		errs = append(errs, berrs...)
+		if p, ok := prepared[b]; ok {
+			p.CommitReload()
+		}
+		if len(changes) > 0 {
+			b.notify(changes)
+			all = append(all, changes...)
+		}
+	}
 
-func (c *Config) bindFloat64(elem reflect.Value, tag string) bool {
-	value, ok := c.cache.getFloat(tag, c.mask)
-	if ok {
-		cur := elem.Float()
-		elem.SetFloat(value)
-		return cur != 0 && cur != value
+	c.publish(old, revision, trigger)
+
+	if len(restart) > 0 {
+		errs = append(errs, &RestartRequiredError{uniqueKeys(restart)})
 	}
 
-	return false
+	return all, errs
 }
 
-func (c *Config) bindBool(elem reflect.Value, tag string) bool {
-	value, ok := c.cache.getBool(tag, c.mask)
-	if ok {
-		cur := elem.Bool()
-		elem.SetBool(value)
-		return !cur && cur != value
+// affected returns the bound instances, in dependency
+// order, which read any key that differs between the
+// old and the cached configuration values.
+func (c *Config) affected(old *Values) []*Binding {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	mode := c.mask.fuzzy()
+	changed := make(map[string]bool)
+	for _, ev := range diffValues(old, c.cache) {
+		changed[mode.normalize(ev.Key)] = true
 	}
 
-	return false
+	var affected []*Binding
+	for _, b := range orderBindings(c.binders) {
+		if b.affected(changed) {
+			affected = append(affected, b)
+		}
+	}
+
+	return affected
 }
 
-func (c *Config) apply() {
-	c.build()
+// commit updates every variable and snapshot to the
+// cached configuration values, and increments the
+// revision. It returns the keys of snapshot fields
+// tagged `reload:"false"` which would have changed,
+// the errors of snapshot fields which couldn't be
+// bound, and the new revision.
+func (c *Config) commit() ([]string, []error, uint64) {
+	c.m.Lock()
+	defer c.m.Unlock()
 
-	for _, v := range c.binders {
-		c.bind(v)
+	var restart []string
+	var errs []error
+	for _, v := range c.vars {
+		v.update(c.cache, c.mask)
+	}
+	for _, s := range c.snapshots {
+		keys, serrs := s.reload(c.core)
+		restart = append(restart, keys...)
+		errs = append(errs, serrs...)
 	}
+
+	c.revision++
+
+	return restart, errs, c.revision
 }
 
-func isSliceEqual(as interface{}, bs interface{}) bool {
-	a := as.([]interface{})
-	b := bs.([]interface{})
+// publish sends the changes from the old to the cached
+// configuration values to every subscription and stream.
+func (c *Config) publish(old *Values, revision uint64, trigger string) {
+	c.m.Lock()
+	next := c.cache
+	subs := append([]*subscription(nil), c.subs...)
+	streams := append([]*stream(nil), c.streams...)
+	c.m.Unlock()
 
-	if len(a) != len(b) {
-		return false
+	for _, s := range subs {
+		s.publish(old, next, c.mask)
 	}
 
-	for i := range a {
-		if a[i] != b[i] {
-			return false
+	now := time.Now()
+	for _, s := range streams {
+		s.publish(old, next, c.mask, ChangeBatch{
+			Revision: revision,
+			Time:     now,
+			Trigger:  trigger,
+		})
+	}
+}
+
+// prepare passes a copy of every instance which implements
+// Preparer and would change, bound to the cached values, to
+// PrepareReload. It returns the instances to commit, or a
+// ReloadAbortedError if any instance fails to prepare.
+func (c *Config) prepare(binders []*Binding) (map[*Binding]Preparer, error) {
+	prepared := make(map[*Binding]Preparer)
+
+	for _, b := range binders {
+		p, ok := b.v.Interface().(Preparer)
+		if !ok {
+			continue
+		}
+
+		next, changes := c.bindCopy(b)
+		if len(changes) == 0 {
+			continue
+		}
+
+		if err := p.PrepareReload(next); err != nil {
+			return nil, &ReloadAbortedError{b.v.Interface(), err}
 		}
+		prepared[b] = p
 	}
 
-	return true
+	return prepared, nil
 }
 
-func newFileWatcher(fn func(), errfn func(error)) *fsnotify.Watcher {
-	w, err := fsnotify.NewWatcher()
-	if err != nil {
-		errfn(err)
-		return nil
+// abort restores the old configuration values after a
+// failed prepare, and makes the next parse of every source
+// count as a change, so that the reload is retried.
+func (c *Config) abort(old *Values) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.cache = old
+	for _, s := range c.sources {
+		s.parsed = false
 	}
+}
 
-	go func() {
-		for {
-			select {
-			case event, ok := <-w.Events:
-				if !ok {
-					return
-				}
-				// Only trigger on write events (file modifications)
-				if event.Has(fsnotify.Write) {
-					fn()
-				}
-			case err, ok := <-w.Errors:
-				if !ok {
-					return
-				}
-				errfn(err)
-			}
-		}
-	}()
+// notify calls NotifyChanges and Notify on out,
+// for each interface that out implements.
+func notify(out interface{}, changes ChangeSet) {
+	if n, ok := out.(ChangeNotifier); ok {
+		n.NotifyChanges(changes)
+	}
 
-	return w
+	if n, ok := out.(Notifier); ok {
+		n.Notify()
+	}
 }
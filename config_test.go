@@ -1,7 +1,17 @@
 package binder
 
 import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -90,7 +100,7 @@ func Test_Use(t *testing.T) {
 		c.Use(fake)
 	}
 
-	assert.Equal(t, len(fakes), len(c.parsers))
+	assert.Equal(t, len(fakes), len(c.sources))
 }
 
 func Test_Build(t *testing.T) {
@@ -207,7 +217,7 @@ func Test_Rebind_Value(t *testing.T) {
 	c.Bind(&b)
 
 	p.value = "value2"
-	c.apply()
+	c.apply("test")
 
 	assert.Equal(t, "value2", b.ValueField)
 }
@@ -220,7 +230,7 @@ func Test_Rebind_Notify(t *testing.T) {
 	c.Bind(&b)
 
 	p.value = "value2"
-	c.apply()
+	c.apply("test")
 
 	assert.True(t, b.notified)
 }
@@ -231,7 +241,656 @@ func Test_Rebind_Notify_NoOp(t *testing.T) {
 
 	var b fakeBinder
 	c.Bind(&b)
-	c.apply()
+	c.apply("test")
 
 	assert.False(t, b.notified)
 }
+
+func Test_BindMode_IgnoreCase_Precedence(t *testing.T) {
+	m1 := make(map[string]interface{})
+	m1["db_host"] = "file"
+
+	m2 := make(map[string]interface{})
+	m2["DB_HOST"] = "env"
+
+	c := New(
+		WithParser(newFakeParser(m1)),
+		WithParser(newFakeParser(m2)))
+
+	for i := 0; i < 20; i++ {
+		c.build()
+
+		var result struct {
+			Host string `config:"Db_Host"`
+		}
+		c.Bind(&result)
+
+		assert.Equal(t, "env", result.Host)
+	}
+}
+
+func Test_BindMode_IgnoreCase_PrefersExactCase(t *testing.T) {
+	m1 := make(map[string]interface{})
+	m1["db_host"] = "file"
+
+	m2 := make(map[string]interface{})
+	m2["DB_HOST"] = "env"
+
+	c := New(
+		WithParser(newFakeParser(m1)),
+		WithParser(newFakeParser(m2)))
+
+	var result struct {
+		Host string `config:"db_host"`
+	}
+	c.Bind(&result)
+
+	assert.Equal(t, "file", result.Host)
+}
+
+func Test_Build_Collision_Warning(t *testing.T) {
+	m1 := make(map[string]interface{})
+	m1["db_host"] = "file"
+
+	m2 := make(map[string]interface{})
+	m2["DB_HOST"] = "env"
+
+	c := New(
+		WithParser(newFakeParser(m1)),
+		WithParser(newFakeParser(m2)),
+		WithCollisionWarnings())
+	c.build()
+
+	err := <-c.Errors()
+	assert.Equal(t, &KeyCollisionError{[]string{"db_host", "DB_HOST"}}, err)
+	assert.Equal(t, "configuration keys collide: db_host, DB_HOST (using an exact match, otherwise DB_HOST)", err.Error())
+}
+
+func Test_BindMode_IgnoreSeparators(t *testing.T) {
+	keys := []string{"max_conns", "max-conns", "maxConns", "MAX__CONNS", "max.conns"}
+
+	for _, key := range keys {
+		m := make(map[string]interface{})
+		m[key] = "10"
+
+		c := New(
+			WithParser(newFakeParser(m)),
+			WithBindMode(ModeIgnoreCase|ModeIgnoreSeparators))
+
+		var result struct {
+			MaxConns int `config:"max_conns"`
+		}
+		c.Bind(&result)
+
+		assert.Equal(t, 10, result.MaxConns, key)
+	}
+}
+
+func Test_BindMode_IgnoreSeparators_CaseSensitive(t *testing.T) {
+	m := make(map[string]interface{})
+	m["MAX_CONNS"] = "10"
+
+	c := New(
+		WithParser(newFakeParser(m)),
+		WithBindMode(ModeIgnoreSeparators))
+
+	var result struct {
+		MaxConns int `config:"maxconns"`
+	}
+	c.Bind(&result)
+
+	assert.Equal(t, 0, result.MaxConns)
+}
+
+func Test_Sub_Values(t *testing.T) {
+	m := make(map[string]interface{})
+	m["payments.host"] = "payments-host"
+	m["search.host"] = "search-host"
+
+	c := New(WithParser(newFakeParser(m)))
+
+	v, ok := c.Sub("payments").Values().Get("host")
+	assert.True(t, ok)
+	assert.Equal(t, "payments-host", v)
+
+	_, ok = c.Sub("payments").Values().Get("search.host")
+	assert.False(t, ok)
+}
+
+func Test_Sub_Nested(t *testing.T) {
+	m := make(map[string]interface{})
+	m["services.payments.host"] = "payments-host"
+
+	c := New(WithParser(newFakeParser(m)))
+
+	v, ok := c.Sub("services").Sub("payments").Values().Get("host")
+	assert.True(t, ok)
+	assert.Equal(t, "payments-host", v)
+}
+
+func Test_Sub_Close(t *testing.T) {
+	c := New()
+	defer c.Close()
+
+	c.Sub("").Close()
+	c.Sub("payments").Close()
+
+	assert.NoError(t, c.ctx.Err())
+}
+
+type fakePrefixedRebindParser struct {
+	value string
+}
+
+func (p *fakePrefixedRebindParser) Parse() (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	m["PAYMENTS_BINDER_KEY"] = p.value
+
+	return m, nil
+}
+
+func Test_Sub_Rebind(t *testing.T) {
+	p := &fakePrefixedRebindParser{"value1"}
+	c := New(
+		WithParser(p),
+		WithBindMode(ModeIgnoreCase|ModeIgnoreSeparators))
+
+	var b fakeBinder
+	c.Sub("payments").Bind(&b)
+	assert.Equal(t, "value1", b.ValueField)
+
+	p.value = "value2"
+	c.apply("test")
+
+	assert.Equal(t, "value2", b.ValueField)
+	assert.True(t, b.notified)
+}
+
+type fakeMultiParser struct {
+	m map[string]interface{}
+}
+
+func (p *fakeMultiParser) Parse() (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	for k, v := range p.m {
+		m[k] = v
+	}
+
+	return m, nil
+}
+
+type fakeChangeBinder struct {
+	First   string   `config:"first"`
+	Enabled bool     `config:"enabled"`
+	Hosts   []string `config:"hosts"`
+	Last    string   `config:"last"`
+	changes ChangeSet
+}
+
+func (f *fakeChangeBinder) NotifyChanges(changes ChangeSet) {
+	f.changes = changes
+}
+
+func Test_Rebind_NotifyChanges(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"first":   "a",
+		"enabled": true,
+		"hosts":   []string{"a", "b"},
+		"last":    "z",
+	}}
+	c := New(WithParser(p))
+
+	var b fakeChangeBinder
+	c.Bind(&b)
+	assert.Nil(t, b.changes)
+
+	p.m["first"] = "b"
+	p.m["enabled"] = false
+	p.m["hosts"] = []string{"a", "c"}
+	c.apply("test")
+
+	assert.Equal(t, ChangeSet{
+		{Path: "First", Key: "first", Old: "a", New: "b"},
+		{Path: "Enabled", Key: "enabled", Old: true, New: false},
+		{Path: "Hosts", Key: "hosts", Old: []string{"a", "b"}, New: []string{"a", "c"}},
+	}, b.changes)
+	assert.False(t, b.changes.Has("Last"))
+}
+
+type fakeFirstFieldBinder struct {
+	First    string `config:"first"`
+	Last     string `config:"last"`
+	notified bool
+}
+
+func (f *fakeFirstFieldBinder) Notify() {
+	f.notified = true
+}
+
+func Test_Rebind_Notify_FirstField(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"first": "a",
+		"last":  "z",
+	}}
+	c := New(WithParser(p))
+
+	var b fakeFirstFieldBinder
+	c.Bind(&b)
+
+	p.m["first"] = "b"
+	c.apply("test")
+
+	assert.True(t, b.notified)
+}
+
+type fakeErrParser struct {
+	err error
+}
+
+func (p *fakeErrParser) Parse() (map[string]interface{}, error) {
+	return nil, p.err
+}
+
+func Test_Reload(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"first": "a",
+		"last":  "z",
+	}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var b1 fakeChangeBinder
+	var b2 fakeFirstFieldBinder
+	c.Bind(&b1, &b2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := c.Changes(ctx)
+
+	p.m["first"] = "b"
+	changes, err := c.Reload(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, ChangeSet{
+		{Path: "First", Key: "first", Old: "a", New: "b"},
+		{Path: "First", Key: "first", Old: "a", New: "b"},
+	}, changes)
+	assert.True(t, b2.notified)
+	assert.Equal(t, TriggerManual, receiveBatch(t, ch).Trigger)
+}
+
+func Test_Reload_Error(t *testing.T) {
+	err := errors.New("unreachable")
+	c := New(WithParser(&fakeErrParser{}))
+	defer c.Close()
+
+	c.Values()
+	c.sources[0].p.(*fakeErrParser).err = err
+
+	_, rerr := c.Reload(context.Background())
+	assert.ErrorIs(t, rerr, err)
+
+	select {
+	case err := <-c.Errors():
+		t.Fatalf("unexpected error on channel: %v", err)
+	default:
+	}
+}
+
+func Test_Reload_Canceled(t *testing.T) {
+	c := New()
+	defer c.Close()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_ = c.reloads.do(context.Background(), func() {
+			close(started)
+			<-release
+		})
+	}()
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.Reload(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+type fakeFlakyParser struct {
+	m   map[string]interface{}
+	err error
+}
+
+func (p *fakeFlakyParser) Parse() (map[string]interface{}, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.m, nil
+}
+
+func Test_Build_LastKnownGood(t *testing.T) {
+	p := &fakeFlakyParser{m: map[string]interface{}{"first": "b"}}
+	c := New(WithParser(&fakeMultiParser{map[string]interface{}{"first": "a"}}), WithParser(p))
+	defer c.Close()
+
+	var b fakeFirstFieldBinder
+	c.Bind(&b)
+	assert.Equal(t, "b", b.First)
+
+	p.err = errors.New("503 service unavailable")
+	_, err := c.Reload(context.Background())
+
+	var stale *StaleSourceError
+	assert.ErrorAs(t, err, &stale)
+	assert.ErrorIs(t, err, p.err)
+	assert.Equal(t, "b", b.First)
+
+	v := c.Values().Lookup("first")
+	assert.Equal(t, "b", v.v)
+	assert.True(t, v.Stale())
+
+	p.err = nil
+	_, err = c.Reload(context.Background())
+	assert.NoError(t, err)
+
+	v = c.Values().Lookup("first")
+	assert.False(t, v.Stale())
+}
+
+func Test_Build_StaleChange(t *testing.T) {
+	p := &fakeFlakyParser{m: map[string]interface{}{"first": "b"}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var events []ChangeEvent
+	c.OnChange("first", func(ev ChangeEvent) {
+		events = append(events, ev)
+	})
+
+	p.err = errors.New("503 service unavailable")
+	_, _ = c.Reload(context.Background())
+
+	assert.Len(t, events, 1)
+	assert.False(t, events[0].Old.Stale())
+	assert.True(t, events[0].New.Stale())
+
+	p.err = nil
+	_, _ = c.Reload(context.Background())
+
+	assert.Len(t, events, 2)
+	assert.True(t, events[1].Old.Stale())
+	assert.False(t, events[1].New.Stale())
+}
+
+func Test_Build_MaxStaleness(t *testing.T) {
+	p := &fakeFlakyParser{m: map[string]interface{}{"first": "b"}}
+	c := New(WithParser(&fakeMultiParser{map[string]interface{}{"first": "a"}}), WithParser(p, MaxStaleness(time.Millisecond)))
+	defer c.Close()
+
+	var b fakeFirstFieldBinder
+	c.Bind(&b)
+
+	time.Sleep(5 * time.Millisecond)
+	p.err = errors.New("503 service unavailable")
+	_, err := c.Reload(context.Background())
+
+	var stale *StaleSourceError
+	assert.False(t, errors.As(err, &stale))
+	assert.ErrorIs(t, err, p.err)
+	assert.Equal(t, "a", b.First)
+}
+
+type fakeFrozenBinder struct {
+	Port    int    `config:"port" reload:"false"`
+	Host    string `config:"host"`
+	changes ChangeSet
+}
+
+func (f *fakeFrozenBinder) NotifyChanges(changes ChangeSet) {
+	f.changes = changes
+}
+
+func Test_Rebind_ReloadFalse(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"port": 8080,
+		"host": "a",
+	}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var b fakeFrozenBinder
+	c.Bind(&b)
+	assert.Equal(t, 8080, b.Port)
+
+	p.m["port"] = 9090
+	p.m["host"] = "b"
+	changes, err := c.Reload(context.Background())
+
+	var restart *RestartRequiredError
+	assert.ErrorAs(t, err, &restart)
+	assert.Equal(t, []string{"port"}, restart.Keys)
+	assert.Equal(t, 8080, b.Port)
+	assert.Equal(t, "b", b.Host)
+	assert.Equal(t, ChangeSet{{Path: "Host", Key: "host", Old: "a", New: "b"}}, changes)
+	assert.Equal(t, changes, b.changes)
+
+	p.m["port"] = 8080
+	_, err = c.Reload(context.Background())
+	assert.NoError(t, err)
+}
+
+func Test_Rebind_ReloadFalse_Errors(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{"port": 8080}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var b fakeFrozenBinder
+	c.Bind(&b)
+
+	p.m["port"] = 9090
+	c.apply("test")
+
+	err := <-c.Errors()
+	assert.Equal(t, &RestartRequiredError{[]string{"port"}}, err)
+	assert.Equal(t, 8080, b.Port)
+}
+
+type fakeCountingBinder struct {
+	Host     string `config:"host"`
+	notified int
+}
+
+func (f *fakeCountingBinder) Notify() {
+	f.notified++
+}
+
+func Test_Rebind_OnlyAffected(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"db.host":    "a",
+		"cache.host": "x",
+		"other":      "1",
+	}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var db, cache fakeCountingBinder
+	c.Sub("db").Bind(&db)
+	c.Sub("cache").Bind(&cache)
+
+	// An instance which isn't re-bound keeps local edits.
+	cache.Host = "local"
+
+	p.m["other"] = "2"
+	changes, err := c.Reload(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, "local", cache.Host)
+	assert.Equal(t, 0, db.notified)
+	assert.Equal(t, 0, cache.notified)
+
+	delete(p.m, "db.host")
+	p.m["DB.HOST"] = "b"
+	_, err = c.Reload(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "b", db.Host)
+	assert.Equal(t, 1, db.notified)
+	assert.Equal(t, "local", cache.Host)
+	assert.Equal(t, 0, cache.notified)
+}
+
+// goroutineLeaks returns a func which fails the test if
+// more goroutines are running than when it was created,
+// after giving exiting goroutines some time.
+func goroutineLeaks(t *testing.T) func() {
+	before := runtime.NumGoroutine()
+
+	return func() {
+		t.Helper()
+
+		deadline := time.Now().Add(2 * time.Second)
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		if n := runtime.NumGoroutine(); n > before {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("%d goroutines leaked:\n%s", n-before, buf[:runtime.Stack(buf, true)])
+		}
+	}
+}
+
+type fakeTickingParser struct {
+	n atomic.Int64
+}
+
+func (p *fakeTickingParser) Parse() (map[string]interface{}, error) {
+	return map[string]interface{}{"host": strconv.FormatInt(p.n.Add(1), 10)}, nil
+}
+
+type fakeAtomicNotifier struct {
+	Host     string `config:"host"`
+	notified atomic.Int64
+}
+
+func (f *fakeAtomicNotifier) Notify() {
+	f.notified.Add(1)
+}
+
+func Test_Close_NoLeak(t *testing.T) {
+	// The signal package starts a goroutine on first
+	// use, which runs until the process exits.
+	warm := make(chan os.Signal, 1)
+	signal.Notify(warm, syscall.SIGHUP)
+	signal.Stop(warm)
+
+	check := goroutineLeaks(t)
+
+	dir := t.TempDir()
+	reloads := make(chan string)
+	c := New(
+		WithParser(&fakeTickingParser{}, RefreshInterval(time.Millisecond)),
+		WithWatch(dir),
+		WithWatch(filepath.Join(dir, "polled"), WatchPolling()),
+		WithTrigger(NewTimerTrigger(time.Millisecond)),
+		WithTrigger(NewChanTrigger(reloads)),
+		WithTrigger(NewPollTrigger(time.Millisecond, dir)),
+		WithTrigger(NewFileTrigger(dir)),
+		WithSignalReload(),
+		WithReloadDebounce(time.Millisecond))
+
+	var out fakeAtomicNotifier
+	c.Bind(&out)
+	ch := c.Changes(context.Background())
+
+	time.Sleep(20 * time.Millisecond)
+	c.Close()
+
+	for range ch {
+	}
+
+	check()
+}
+
+func Test_Close_NoCallbacks(t *testing.T) {
+	c := New(
+		WithParser(&fakeTickingParser{}),
+		WithTrigger(NewTimerTrigger(time.Millisecond)))
+
+	var out fakeAtomicNotifier
+	c.Bind(&out)
+
+	var events atomic.Int64
+	c.OnChange("*", func(ChangeEvent) {
+		events.Add(1)
+	})
+
+	time.Sleep(20 * time.Millisecond)
+	c.Close()
+
+	notified, published := out.notified.Load(), events.Load()
+	time.Sleep(20 * time.Millisecond)
+
+	assert.Equal(t, notified, out.notified.Load())
+	assert.Equal(t, published, events.Load())
+
+	_, err := c.Reload(context.Background())
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func Test_Close_Idempotent(t *testing.T) {
+	c := New()
+	c.Close()
+	c.Close()
+
+	c.errs(errors.New("after close"))
+	c.Watch(t.TempDir())
+	c.Use(&fakeMultiParser{}, RefreshInterval(time.Millisecond))
+
+	_, ok := <-c.Errors()
+	assert.False(t, ok)
+
+	_, ok = <-c.Changes(context.Background())
+	assert.False(t, ok)
+}
+
+func Test_Run(t *testing.T) {
+	check := goroutineLeaks(t)
+
+	c := New(WithTrigger(NewTimerTrigger(time.Millisecond)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	_, ok := <-c.Errors()
+	assert.False(t, ok)
+
+	check()
+}
+
+func Test_Start(t *testing.T) {
+	check := goroutineLeaks(t)
+
+	c := New(WithTrigger(NewTimerTrigger(time.Millisecond)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Start(ctx)
+	cancel()
+
+	select {
+	case _, ok := <-c.Errors():
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("not closed")
+	}
+
+	check()
+}
@@ -0,0 +1,462 @@
+package binder
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+const configStructTagName string = "config"
+
+// reloadTagName is the struct tag name which marks a
+// field that keeps its value on reload with `false`.
+const reloadTagName string = "reload"
+
+// compatibleTagNames returns the struct tag names used
+// by WithCompatibleTagNames, in order of precedence.
+func compatibleTagNames() []string {
+	return []string{configStructTagName, "env", "mapstructure", "json", "yaml"}
+}
+
+// DecodeOption is passed to Values.Decode, and
+// used through functional parameters.
+type DecodeOption func(*decoder)
+
+// WithDecodeMode sets the BindMode used when
+// matching configuration keys to struct tags
+// during a decode. Default is DefaultBindMode.
+func WithDecodeMode(mode BindMode) DecodeOption {
+	return func(d *decoder) {
+		d.mode = mode
+	}
+}
+
+// WithDecodeTagNames sets the struct tag names used
+// to find configuration keys during a decode, in order
+// of precedence. Default is `config`.
+func WithDecodeTagNames(names ...string) DecodeOption {
+	return func(d *decoder) {
+		d.tags = names
+	}
+}
+
+// decoder holds the binding engine shared by
+// Config.Bind and Values.Decode.
+type decoder struct {
+	values *Values
+	mode   BindMode
+	tags   []string
+	prefix string
+
+	// reload is true when re-binding, and restart
+	// holds the keys of fields tagged `reload:"false"`
+	// which would have changed.
+	reload  bool
+	restart []string
+
+	// keys holds every key read while binding, and
+	// errs holds the fields which couldn't be bound.
+	keys []string
+	errs []error
+}
+
+func newDecoder(values *Values, mode BindMode, tags []string) *decoder {
+	return &decoder{values: values, mode: mode, tags: tags}
+}
+
+// lookupTag returns the configuration key of a struct
+// field from the first tag name present on the field
+// with a non-empty key, such as `yaml:"port"` after
+// `json:",omitempty"`. Tag options following a comma
+// are ignored, and a key of `-` skips the field.
+func (d *decoder) lookupTag(f reflect.StructField) (string, bool) {
+	for _, name := range d.tags {
+		tag, ok := f.Tag.Lookup(name)
+		if !ok {
+			continue
+		}
+
+		if i := strings.Index(tag, ","); i >= 0 {
+			tag = tag[:i]
+		}
+
+		if tag == "-" {
+			return "", false
+		}
+
+		if tag != "" {
+			return tag, true
+		}
+	}
+
+	return "", false
+}
+
+// Decode binds the configuration values onto out,
+// which must be a pointer to a struct or to a map
+// with string keys. Unlike Config.Bind, out is not
+// registered for re-binding when configuration changes.
+func (v *Values) Decode(out interface{}, opts ...DecodeOption) error {
+	d := newDecoder(v, DefaultBindMode, []string{configStructTagName})
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("cannot decode to non-pointer or nil")
+	}
+
+	elem := rv.Elem()
+	switch elem.Kind() {
+	case reflect.Struct:
+		d.bindStruct(elem)
+		return errors.Join(d.errs...)
+	case reflect.Map:
+		return d.bindMap(elem)
+	}
+
+	return fmt.Errorf("cannot decode to %s", elem.Type())
+}
+
+func (d *decoder) bindMap(elem reflect.Value) error {
+	t := elem.Type()
+	if t.Key().Kind() != reflect.String {
+		return fmt.Errorf("cannot decode to map with %s keys", t.Key())
+	}
+
+	if elem.IsNil() {
+		elem.Set(reflect.MakeMapWithSize(t, len(d.values.m)))
+	}
+
+	for k, value := range d.values.m {
+		var rv reflect.Value
+		switch t.Elem().Kind() {
+		case reflect.Interface:
+			if value.v == nil {
+				rv = reflect.Zero(t.Elem())
+			} else {
+				rv = reflect.ValueOf(value.v)
+			}
+		case reflect.String:
+			s, _ := value.String()
+			rv = reflect.ValueOf(s)
+		default:
+			return fmt.Errorf("cannot decode to map with %s values", t.Elem())
+		}
+
+		elem.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), rv.Convert(t.Elem()))
+	}
+
+	return nil
+}
+
+func (d *decoder) bindStruct(elem reflect.Value) ChangeSet {
+	var changes ChangeSet
+
+	t := elem.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := d.lookupTag(t.Field(i))
+		if !ok {
+			continue
+		}
+
+		field := elem.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+
+		key := joinKey(d.prefix, tag)
+		d.keys = append(d.keys, key)
+
+		// A field which must not change at runtime is bound
+		// to a copy on reload, to find out if it would change.
+		frozen := d.reload && isFrozen(t.Field(i))
+		if frozen {
+			field = cloneField(field)
+		}
+
+		old, next, ok := d.bindField(field, tag)
+		if !ok || reflect.DeepEqual(old, next) {
+			continue
+		}
+
+		if frozen {
+			d.restart = append(d.restart, key)
+			continue
+		}
+
+		changes = append(changes, Change{
+			Path: t.Field(i).Name,
+			Key:  key,
+			Old:  old,
+			New:  next,
+		})
+	}
+
+	return changes
+}
+
+// bindField binds a configuration value to a field, and
+// returns its old and new value. It returns false as third
+// return value if the field is of an unsupported type, or
+// if the key is missing.
+func (d *decoder) bindField(field reflect.Value, tag string) (interface{}, interface{}, bool) {
+	if old, next, ok := d.bindStore(field, tag); ok {
+		return old, next, true
+	}
+
+	old := reflect.New(field.Type()).Elem()
+	old.Set(field)
+
+	if !d.bindValue(field, tag) {
+		return nil, nil, false
+	}
+
+	return old.Interface(), field.Interface(), true
+}
+
+// isFrozen returns true if a field is tagged
+// `reload:"false"`, and keeps its value on reload.
+func isFrozen(f reflect.StructField) bool {
+	return f.Tag.Get(reloadTagName) == "false"
+}
+
+// cloneField returns a settable copy of a field,
+// which doesn't share a *slog.LevelVar with it.
+func cloneField(field reflect.Value) reflect.Value {
+	c := reflect.New(field.Type()).Elem()
+	if lv, ok := field.Interface().(*slog.LevelVar); ok {
+		if lv != nil {
+			clone := new(slog.LevelVar)
+			clone.Set(lv.Level())
+			c.Set(reflect.ValueOf(clone))
+		}
+		return c
+	}
+
+	c.Set(field)
+	return c
+}
+
+// keepFrozen copies every field tagged `reload:"false"`
+// from src to dst, which are structs of the same type.
+func keepFrozen(dst reflect.Value, src reflect.Value) {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if isFrozen(t.Field(i)) && dst.Field(i).CanSet() {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+}
+
+func (d *decoder) bindValue(elem reflect.Value, tag string) bool {
+	switch elem.Kind() {
+	case reflect.String:
+		return d.bindString(elem, tag)
+	case reflect.Slice:
+		return d.bindStringArray(elem, tag)
+	case reflect.Int:
+		return d.bindInt(elem, tag)
+	case reflect.Float32, reflect.Float64:
+		return d.bindFloat(elem, tag)
+	case reflect.Bool:
+		return d.bindBool(elem, tag)
+	}
+
+	return false
+}
+
+func (d *decoder) bindString(elem reflect.Value, tag string) bool {
+	value, ok := d.values.getString(tag, d.mode)
+	if ok {
+		elem.SetString(value)
+	}
+
+	return ok
+}
+
+func (d *decoder) bindStringArray(elem reflect.Value, tag string) bool {
+	if elem.Type().Elem().Kind() != reflect.String {
+		return false
+	}
+
+	v := d.values.lookup(tag, d.mode)
+	if v == nil {
+		return false
+	}
+
+	value, ok := v.StringArray()
+	if !ok {
+		d.errs = append(d.errs, fmt.Errorf("cannot bind %s to %s from %T", joinKey(d.prefix, tag), elem.Type(), v.v))
+		return false
+	}
+
+	elem.Set(reflect.ValueOf(value).Convert(elem.Type()))
+
+	return true
+}
+
+func (d *decoder) bindInt(elem reflect.Value, tag string) bool {
+	value, ok := d.values.getInt(tag, d.mode)
+	if ok {
+		elem.SetInt(int64(value))
+	}
+
+	return ok
+}
+
+func (d *decoder) bindFloat(elem reflect.Value, tag string) bool {
+	value, ok := d.values.getFloat(tag, d.mode)
+	if ok {
+		elem.SetFloat(value)
+	}
+
+	return ok
+}
+
+func (d *decoder) bindBool(elem reflect.Value, tag string) bool {
+	value, ok := d.values.getBool(tag, d.mode)
+	if ok {
+		elem.SetBool(value)
+	}
+
+	return ok
+}
+
+// atomicStore is implemented by the sync/atomic
+// types bound by storeAtomic.
+type atomicStore[T any] interface {
+	Load() T
+	Store(T)
+}
+
+// bindStore sets fields of the sync/atomic types and
+// slog.LevelVar through their Store and Set methods,
+// which makes them safe to read while re-binding. It
+// returns false as third return value if the field is
+// of any other type, and returns equal old and new
+// values if the key is missing.
+func (d *decoder) bindStore(elem reflect.Value, tag string) (interface{}, interface{}, bool) {
+	switch f := elem.Addr().Interface().(type) {
+	case **slog.LevelVar:
+		if *f == nil {
+			*f = new(slog.LevelVar)
+		}
+		return d.bindLevelVar(*f, tag)
+	case *slog.LevelVar:
+		return d.bindLevelVar(f, tag)
+	case *atomic.Bool:
+		value, ok := d.values.getBool(tag, d.mode)
+		return storeAtomic[bool](f, value, ok)
+	case *atomic.Value:
+		return d.bindAtomicValue(f, tag)
+	case *atomic.Pointer[string]:
+		return d.bindAtomicString(f, tag)
+	}
+
+	return d.bindAtomicInt(elem, tag)
+}
+
+// bindAtomicInt sets fields of the sync/atomic integer
+// types, and returns false as third return value if the
+// field is of any other type. Negative values are not
+// stored in unsigned fields.
+func (d *decoder) bindAtomicInt(elem reflect.Value, tag string) (interface{}, interface{}, bool) {
+	switch f := elem.Addr().Interface().(type) {
+	case *atomic.Int64:
+		value, ok := d.values.getInt(tag, d.mode)
+		return storeAtomic[int64](f, int64(value), ok)
+	case *atomic.Int32:
+		value, ok := d.values.getInt(tag, d.mode)
+		return storeAtomic[int32](f, int32(value), ok)
+	case *atomic.Uint64:
+		value, ok := d.values.getInt(tag, d.mode)
+		return storeAtomic[uint64](f, uint64(value), ok && value >= 0)
+	case *atomic.Uint32:
+		value, ok := d.values.getInt(tag, d.mode)
+		return storeAtomic[uint32](f, uint32(value), ok && value >= 0)
+	}
+
+	return nil, nil, false
+}
+
+// storeAtomic stores value in f if ok is true, and
+// returns the old and new values of f.
+func storeAtomic[T any](f atomicStore[T], value T, ok bool) (interface{}, interface{}, bool) {
+	old := f.Load()
+	if ok {
+		f.Store(value)
+	}
+
+	return old, f.Load(), true
+}
+
+// bindAtomicString stores a new pointer in an
+// atomic.Pointer[string] only if the value changed.
+func (d *decoder) bindAtomicString(f *atomic.Pointer[string], tag string) (interface{}, interface{}, bool) {
+	old := f.Load()
+	if value, ok := d.values.getString(tag, d.mode); ok && (old == nil || *old != value) {
+		f.Store(&value)
+	}
+
+	return derefString(old), derefString(f.Load()), true
+}
+
+// bindAtomicValue stores a value in an atomic.Value as
+// the type of the value it already holds, which must be
+// an int, float64, bool or string. An empty atomic.Value
+// stores a string. A value of any other type is kept,
+// and reported as an error.
+func (d *decoder) bindAtomicValue(f *atomic.Value, tag string) (interface{}, interface{}, bool) {
+	old := f.Load()
+
+	var value interface{}
+	var ok bool
+	switch old.(type) {
+	case nil, string:
+		value, ok = d.values.getString(tag, d.mode)
+	case int:
+		value, ok = d.values.getInt(tag, d.mode)
+	case float64:
+		value, ok = d.values.getFloat(tag, d.mode)
+	case bool:
+		value, ok = d.values.getBool(tag, d.mode)
+	default:
+		d.errs = append(d.errs, fmt.Errorf("cannot bind %s to atomic.Value holding %T", joinKey(d.prefix, tag), old))
+		return old, old, true
+	}
+
+	if ok {
+		f.Store(value)
+	}
+
+	return old, f.Load(), true
+}
+
+func (d *decoder) bindLevelVar(lv *slog.LevelVar, tag string) (interface{}, interface{}, bool) {
+	old := lv.Level()
+
+	value, ok := d.values.getString(tag, d.mode)
+	if ok {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(value)); err == nil {
+			lv.Set(level)
+		} else if i, ok := d.values.getInt(tag, d.mode); ok {
+			lv.Set(slog.Level(i))
+		}
+	}
+
+	return old, lv.Level(), true
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,293 @@
+package binder
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Values_Decode_Struct(t *testing.T) {
+	m := make(map[string]*Value)
+	m["Name"] = &Value{v: "binder"}
+	m["workers"] = &Value{v: "4"}
+	m["enabled"] = &Value{v: true}
+
+	v := newValues(m, nil)
+
+	var out struct {
+		Name    string `config:"name"`
+		Workers int    `config:"workers"`
+		Enabled bool   `config:"enabled"`
+	}
+	err := v.Decode(&out)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "binder", out.Name)
+	assert.Equal(t, 4, out.Workers)
+	assert.True(t, out.Enabled)
+}
+
+func Test_Values_Decode_Strict(t *testing.T) {
+	m := make(map[string]*Value)
+	m["Name"] = &Value{v: "binder"}
+
+	v := newValues(m, nil)
+
+	var out struct {
+		Name string `config:"name"`
+	}
+	err := v.Decode(&out, WithDecodeMode(ModeStrict))
+	assert.NoError(t, err)
+
+	assert.Empty(t, out.Name)
+}
+
+func Test_Values_Decode_Map(t *testing.T) {
+	m := make(map[string]*Value)
+	m["key"] = &Value{v: "value"}
+	m["int"] = &Value{v: 100}
+
+	v := newValues(m, nil)
+
+	var out map[string]interface{}
+	err := v.Decode(&out)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"key": "value", "int": 100}, out)
+}
+
+func Test_Values_Decode_Sub(t *testing.T) {
+	m := make(map[string]*Value)
+	m["plugins.x.name"] = &Value{v: "x"}
+	m["plugins.y.name"] = &Value{v: "y"}
+
+	v := newValues(m, nil)
+
+	var out struct {
+		Name string `config:"name"`
+	}
+	err := v.Sub("plugins.x").Decode(&out)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "x", out.Name)
+}
+
+func Test_Values_Decode_StringsMismatch(t *testing.T) {
+	m := make(map[string]*Value)
+	m["hosts"] = &Value{v: []int{1}}
+	m["name"] = &Value{v: "binder"}
+
+	v := newValues(m, nil)
+
+	var out struct {
+		Hosts []string `config:"hosts"`
+		Name  string   `config:"name"`
+	}
+	err := v.Decode(&out)
+	assert.EqualError(t, err, "cannot bind hosts to []string from []int")
+
+	assert.Nil(t, out.Hosts)
+	assert.Equal(t, "binder", out.Name)
+}
+
+func Test_Values_Decode_NonPointer(t *testing.T) {
+	v := newValues(make(map[string]*Value), nil)
+
+	var out struct{}
+	err := v.Decode(out)
+	assert.Error(t, err)
+}
+
+func Test_Values_Decode_Unsupported(t *testing.T) {
+	v := newValues(make(map[string]*Value), nil)
+
+	var out int
+	err := v.Decode(&out)
+	assert.Error(t, err)
+}
+
+func Test_Values_Decode_TagNames(t *testing.T) {
+	m := make(map[string]*Value)
+	m["name"] = &Value{v: "binder"}
+
+	v := newValues(m, nil)
+
+	var out struct {
+		Name string `mapstructure:"name"`
+	}
+	err := v.Decode(&out, WithDecodeTagNames("mapstructure"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "binder", out.Name)
+}
+
+type fakeAtomicBinder struct {
+	Workers  atomic.Int64           `config:"workers"`
+	Shards   atomic.Uint32          `config:"shards"`
+	Enabled  atomic.Bool            `config:"enabled"`
+	Name     atomic.Value           `config:"name"`
+	Region   atomic.Pointer[string] `config:"region"`
+	Level    *slog.LevelVar         `config:"log_level"`
+	Sampling slog.LevelVar          `config:"sampling_level"`
+}
+
+func Test_Values_Decode_Atomic(t *testing.T) {
+	m := make(map[string]*Value)
+	m["workers"] = &Value{v: "8"}
+	m["shards"] = &Value{v: 3}
+	m["enabled"] = &Value{v: "true"}
+	m["name"] = &Value{v: "binder"}
+	m["region"] = &Value{v: "eu"}
+	m["log_level"] = &Value{v: "debug"}
+	m["sampling_level"] = &Value{v: "4"}
+
+	v := newValues(m, nil)
+
+	var out fakeAtomicBinder
+	err := v.Decode(&out)
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(8), out.Workers.Load())
+	assert.Equal(t, uint32(3), out.Shards.Load())
+	assert.True(t, out.Enabled.Load())
+	assert.Equal(t, "binder", out.Name.Load())
+	assert.Equal(t, "eu", *out.Region.Load())
+	assert.Equal(t, slog.LevelDebug, out.Level.Level())
+	assert.Equal(t, slog.LevelWarn, out.Sampling.Level())
+}
+
+func Test_Rebind_Atomic_Race(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"workers":   "1",
+		"log_level": "info",
+	}}
+	c := New(WithParser(p))
+
+	var out fakeAtomicBinder
+	c.Bind(&out)
+	level := out.Level
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = out.Workers.Load()
+				_ = level.Level()
+			}
+		}
+	}()
+
+	p.m["workers"] = "2"
+	p.m["log_level"] = "error"
+	c.apply("test")
+
+	close(done)
+	wg.Wait()
+
+	assert.Equal(t, int64(2), out.Workers.Load())
+	assert.Equal(t, slog.LevelError, out.Level.Level())
+	assert.Same(t, level, out.Level)
+}
+
+type fakeFrozenAtomicBinder struct {
+	Workers atomic.Int64   `config:"workers" reload:"false"`
+	Level   *slog.LevelVar `config:"log_level" reload:"false"`
+	Name    atomic.Value   `config:"name"`
+}
+
+func Test_Decode_ReloadFalse_Atomic(t *testing.T) {
+	m := make(map[string]*Value)
+	m["workers"] = &Value{v: "8"}
+	m["log_level"] = &Value{v: "debug"}
+	m["name"] = &Value{v: "binder"}
+
+	var out fakeFrozenAtomicBinder
+	out.Workers.Store(1)
+	out.Level = new(slog.LevelVar)
+
+	d := newDecoder(newValues(m, nil), DefaultBindMode, []string{configStructTagName})
+	d.reload = true
+	changes := d.bindStruct(reflect.ValueOf(&out).Elem())
+
+	assert.Equal(t, int64(1), out.Workers.Load())
+	assert.Equal(t, slog.LevelInfo, out.Level.Level())
+	assert.Equal(t, "binder", out.Name.Load())
+	assert.ElementsMatch(t, []string{"workers", "log_level"}, d.restart)
+	assert.Len(t, changes, 1)
+}
+
+type fakeAtomicValueBinder struct {
+	Rate    atomic.Value `config:"rate"`
+	Workers atomic.Value `config:"workers"`
+	Enabled atomic.Value `config:"enabled"`
+	Name    atomic.Value `config:"name"`
+	Other   atomic.Value `config:"other"`
+}
+
+func Test_Values_Decode_AtomicValue_Typed(t *testing.T) {
+	m := make(map[string]*Value)
+	m["rate"] = &Value{v: "0.25"}
+	m["workers"] = &Value{v: "8"}
+	m["enabled"] = &Value{v: "true"}
+	m["name"] = &Value{v: "binder"}
+	m["other"] = &Value{v: "x"}
+
+	var out fakeAtomicValueBinder
+	out.Rate.Store(0.5)
+	out.Workers.Store(1)
+	out.Enabled.Store(false)
+	out.Other.Store([]int{1})
+
+	err := newValues(m, nil).Decode(&out)
+
+	assert.EqualError(t, err, "cannot bind other to atomic.Value holding []int")
+	assert.Equal(t, 0.25, out.Rate.Load())
+	assert.Equal(t, 8, out.Workers.Load())
+	assert.Equal(t, true, out.Enabled.Load())
+	assert.Equal(t, "binder", out.Name.Load())
+	assert.Equal(t, []int{1}, out.Other.Load())
+}
+
+func Test_Bind_AtomicValue_Error(t *testing.T) {
+	c := New(WithParser(&fakeMultiParser{map[string]interface{}{"other": "x"}}))
+	defer c.Close()
+
+	var out fakeAtomicValueBinder
+	out.Other.Store([]int{1})
+	c.Bind(&out)
+
+	assert.EqualError(t, <-c.Errors(), "cannot bind other to atomic.Value holding []int")
+	assert.Equal(t, []int{1}, out.Other.Load())
+}
+
+func Test_Reload_AtomicValue_Error(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{"other": "x"}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	var out fakeAtomicValueBinder
+	out.Other.Store([]int{1})
+	c.Bind(&out)
+	<-c.Errors()
+
+	p.m["other"] = "y"
+	_, err := c.Reload(context.Background())
+
+	assert.EqualError(t, err, "cannot bind other to atomic.Value holding []int")
+	select {
+	case err := <-c.Errors():
+		t.Fatalf("unexpected error: %v", err)
+	default:
+	}
+}
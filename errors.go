@@ -0,0 +1,83 @@
+package binder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KeyCollisionError is reported when configuration
+// keys differ only by case, or by separators when
+// binding with ModeIgnoreSeparators. Keys are ordered
+// by precedence. A key matching a struct tag exactly
+// is used when binding, otherwise the last key is.
+type KeyCollisionError struct {
+	Keys []string
+}
+
+func (e *KeyCollisionError) Error() string {
+	return fmt.Sprintf("configuration keys collide: %s (using an exact match, otherwise %s)",
+		strings.Join(e.Keys, ", "), e.Keys[len(e.Keys)-1])
+}
+
+// StaleSourceError is reported when a parser fails,
+// and its values from the last successful parse at
+// Since are used instead. Err is the parser error.
+type StaleSourceError struct {
+	Source string
+	Since  time.Time
+	Err    error
+}
+
+func (e *StaleSourceError) Error() string {
+	return fmt.Sprintf("%s: using values from %s: %v",
+		e.Source, e.Since.Format(time.RFC3339), e.Err)
+}
+
+func (e *StaleSourceError) Unwrap() error {
+	return e.Err
+}
+
+// ReloadAbortedError is reported when a reload is
+// aborted, because PrepareReload of the bound Instance
+// returned Err. No instance is re-bound, and the old
+// configuration values are kept.
+type ReloadAbortedError struct {
+	Instance interface{}
+	Err      error
+}
+
+func (e *ReloadAbortedError) Error() string {
+	return fmt.Sprintf("reload aborted by %T: %v", e.Instance, e.Err)
+}
+
+func (e *ReloadAbortedError) Unwrap() error {
+	return e.Err
+}
+
+// RestartRequiredError is reported when a reload
+// changes the values of fields tagged `reload:"false"`,
+// which keep their values until the process restarts.
+type RestartRequiredError struct {
+	Keys []string
+}
+
+func (e *RestartRequiredError) Error() string {
+	return fmt.Sprintf("restart required to apply configuration keys: %s",
+		strings.Join(e.Keys, ", "))
+}
+
+// uniqueKeys returns keys without duplicates,
+// in the order they first appear.
+func uniqueKeys(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	unique := keys[:0]
+	for _, k := range keys {
+		if !seen[k] {
+			seen[k] = true
+			unique = append(unique, k)
+		}
+	}
+
+	return unique
+}
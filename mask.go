@@ -1,5 +1,7 @@
 package binder
 
+import "strings"
+
 // BindMode is used to determine how to bind to
 // a struct tag.
 type BindMode uint8
@@ -12,6 +14,13 @@ const (
 	// ModeStrict requires case sensitivity when binding
 	// configuration keys to struct tag matches.
 	ModeStrict BindMode = 2
+
+	// ModeIgnoreSeparators ignores the separators `_`, `-`
+	// and `.` when binding configuration keys to struct tag
+	// matches. Combine it with ModeIgnoreCase to have keys
+	// such as `max_conns`, `max-conns`, `maxConns`,
+	// `MAX__CONNS` and `max.conns` match the same tag.
+	ModeIgnoreSeparators BindMode = 4
 )
 
 // DefaultBindMode is the default set of flags used
@@ -21,3 +30,33 @@ const DefaultBindMode = ModeIgnoreCase
 func (po BindMode) has(other BindMode) bool {
 	return po&other != 0
 }
+
+// normalize returns the form of key which is used
+// when matching with the flags in po.
+func (po BindMode) normalize(key string) string {
+	if po.has(ModeIgnoreSeparators) {
+		key = strings.Map(func(r rune) rune {
+			switch r {
+			case '_', '-', '.':
+				return -1
+			}
+			return r
+		}, key)
+	}
+
+	if po.has(ModeIgnoreCase) {
+		key = strings.ToLower(key)
+	}
+
+	return key
+}
+
+// fuzzy returns the flags which affects how keys are
+// normalized, or zero if keys must match exactly.
+func (po BindMode) fuzzy() BindMode {
+	if po.has(ModeStrict) {
+		return 0
+	}
+
+	return po & (ModeIgnoreCase | ModeIgnoreSeparators)
+}
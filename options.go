@@ -3,7 +3,10 @@ package binder
 import (
 	"fmt"
 	"net/url"
+	"os"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/ourstudio-se/binder/parsers"
 	"github.com/spf13/pflag"
@@ -13,11 +16,58 @@ import (
 // Config, and used through functional parameters.
 type Option func(*Config)
 
+// ParserOption is passed along with a Parser,
+// and used through functional parameters.
+type ParserOption func(*source)
+
+// AutoWatch watches the files a Parser reads
+// for changes, if the Parser implements Watchable.
+func AutoWatch() ParserOption {
+	return func(s *source) {
+		s.autoWatch = true
+	}
+}
+
+// RefreshInterval re-reads a Parser at the specified
+// interval, with some jitter, and re-binds only if its
+// values changed. It overrides WithRefreshInterval.
+func RefreshInterval(d time.Duration) ParserOption {
+	return func(s *source) {
+		s.refresh = d
+	}
+}
+
+// MaxStaleness limits how long the values of the
+// last successful parse of a Parser are used while the
+// Parser fails. It overrides WithMaxStaleness.
+func MaxStaleness(d time.Duration) ParserOption {
+	return func(s *source) {
+		s.maxStale = d
+	}
+}
+
 // WithParser is an Option to instantiate a
 // custom parser with a Config.
-func WithParser(p Parser) Option {
+func WithParser(p Parser, opts ...ParserOption) Option {
 	return func(c *Config) {
-		c.Use(p)
+		c.Use(p, opts...)
+	}
+}
+
+// WithParserOptions applies ParserOptions to every
+// parser instantiated by an Option, for Options which
+// don't accept them such as WithEnv and WithURL, e.g.
+// `WithParserOptions(WithURL(u), MaxStaleness(time.Hour))`.
+func WithParserOptions(o Option, opts ...ParserOption) Option {
+	return func(c *Config) {
+		n := len(c.sources)
+		o(c)
+
+		for _, s := range c.sources[n:] {
+			for _, opt := range opts {
+				opt(s)
+			}
+		}
 	}
 }
 
@@ -31,8 +81,8 @@ func WithEnv(prefixes ...string) Option {
 // WithFile is an Option to instantiate a
 // parser which reads a backing file using a
 // specific key/value separator.
-func WithFile(filepath string, sep string) Option {
-	return WithParser(parsers.NewFileParser(filepath, sep))
+func WithFile(filepath string, sep string, opts ...ParserOption) Option {
+	return WithParser(parsers.NewFileParser(filepath, sep), opts...)
 }
 
 // WithFlags is an Option to instantiate a
@@ -52,8 +102,8 @@ func WithFlagSet(flagSet *pflag.FlagSet) Option {
 // WithKubernetesVolume is an Option to instantiate
 // a parser which reads a Kubernetes mounted
 // volume when instantiating a Config.
-func WithKubernetesVolume(path string) Option {
-	return WithParser(parsers.NewKubernetesVolumeParser(path))
+func WithKubernetesVolume(path string, opts ...ParserOption) Option {
+	return WithParser(parsers.NewKubernetesVolumeParser(path), opts...)
 }
 
 // WithURL is an Option to instantiate a
@@ -75,19 +125,148 @@ func WithValue(key string, value interface{}) Option {
 // WithWatch adds a file path watch, which can be
 // used to reload configuration values that originates
 // from a FileParser or a KubernetesVolumeParser when
-// the backing files changes.
-func WithWatch(path string) Option {
+// the backing files changes. Files replaced through a
+// rename, and symlinks being swapped such as in a
+// Kubernetes ConfigMap volume, are followed. Paths
+// which don't exist yet are watched until they appear.
+func WithWatch(path string, opts ...WatchOption) Option {
 	return func(c *Config) {
-		c.Watch(path)
+		c.watches = append(c.watches, watchRequest{path, opts})
+	}
+}
+
+// WithPollInterval sets the interval at which paths
+// watched with WatchPolling are polled, comparing
+// modification time, size and content. Default is
+// two seconds. Paths are also polled when file system
+// events are unavailable, otherwise file system
+// events are used.
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Config) {
+		c.pollInterval = d
 	}
 }
 
 // WithBindMode sets a mode which
 // is used when binding configuration values.
 // To restrict from mapping keys with case insensitivity,
-// pass `ModeStrict` as parameter.
+// pass `ModeStrict` as parameter. To also ignore key
+// separators, pass `ModeIgnoreCase|ModeIgnoreSeparators`.
 func WithBindMode(po BindMode) Option {
 	return func(c *Config) {
 		c.mask = po
 	}
 }
+
+// WithCollisionWarnings reports a KeyCollisionError
+// on the Errors channel whenever configuration keys
+// match the same struct tag under the bind mode, e.g.
+// `DB_HOST` from the environment and `db_host` from
+// a file. A key matching the struct tag exactly is
+// used when binding, otherwise the key from the
+// parser added last is.
+func WithCollisionWarnings() Option {
+	return func(c *Config) {
+		c.warnCollisions = true
+	}
+}
+
+// WithTagName sets the struct tag name used to find
+// the configuration key of a field. Default is `config`.
+func WithTagName(name string) Option {
+	return WithTagNames(name)
+}
+
+// WithTagNames sets several struct tag names used to
+// find the configuration key of a field, in order of
+// precedence. The first tag name present on a field
+// is used.
+func WithTagNames(names ...string) Option {
+	return func(c *Config) {
+		c.tags = names
+	}
+}
+
+// WithCompatibleTagNames makes it possible to bind
+// structs annotated for other libraries, by falling
+// back through the tag names `config`, `env`,
+// `mapstructure`, `json` and `yaml`.
+func WithCompatibleTagNames() Option {
+	return WithTagNames(compatibleTagNames()...)
+}
+
+// WithReloadDebounce coalesces bursts of file changes
+// into a single reload, which runs once no change has
+// been seen for the duration d. Reloads never overlap,
+// regardless of this option.
+func WithReloadDebounce(d time.Duration) Option {
+	return func(c *Config) {
+		c.reloads.debounce = d
+	}
+}
+
+// WithMinReloadInterval sets the minimum duration
+// between the start of two consecutive reloads. File
+// changes arriving sooner are coalesced into a single
+// reload once the interval has passed.
+func WithMinReloadInterval(d time.Duration) Option {
+	return func(c *Config) {
+		c.reloads.minInterval = d
+	}
+}
+
+// WithTrigger adds a custom source of reloads, such
+// as one of NewTimerTrigger, NewSignalTrigger or
+// NewChanTrigger, or a Trigger implemented for a
+// remote or database backed parser.
+func WithTrigger(t Trigger) Option {
+	return func(c *Config) {
+		c.triggers = append(c.triggers, t)
+	}
+}
+
+// WithSignalReload reloads every parser when the
+// process receives any of the specified signals,
+// or SIGHUP if no signals are specified, so that
+// `kill -HUP` re-reads the configuration.
+func WithSignalReload(sigs ...os.Signal) Option {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	return WithTrigger(NewSignalTrigger(sigs...))
+}
+
+// WithAutoWatch watches the files read by every
+// Parser which implements Watchable, such as the
+// parsers added with WithFile and WithKubernetesVolume,
+// without repeating the paths in WithWatch.
+func WithAutoWatch() Option {
+	return func(c *Config) {
+		c.autoWatch = true
+	}
+}
+
+// WithRefreshInterval re-reads every Parser at the
+// specified interval, with some jitter, and re-binds
+// only if any values changed. This keeps values from
+// sources which can't be watched up to date, such as
+// remote files and environment variables. Use
+// RefreshInterval to set the interval per Parser.
+func WithRefreshInterval(d time.Duration) Option {
+	return func(c *Config) {
+		c.refreshInterval = d
+	}
+}
+
+// WithMaxStaleness limits how long the values of the
+// last successful parse of every Parser are used while
+// the Parser fails, after which its values are dropped.
+// By default, the values are used until the Parser
+// succeeds again. Use MaxStaleness to set the limit
+// per Parser.
+func WithMaxStaleness(d time.Duration) Option {
+	return func(c *Config) {
+		c.maxStale = d
+	}
+}
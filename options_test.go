@@ -1,9 +1,15 @@
 package binder
 
 import (
+	"context"
 	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/ourstudio-se/binder/parsers"
 	"github.com/stretchr/testify/assert"
@@ -13,16 +19,16 @@ func Test_WithParser(t *testing.T) {
 	p := &fakeParser{}
 	c := New(WithParser(p))
 
-	assert.Len(t, c.parsers, 1)
-	assert.Equal(t, p, c.parsers[0])
+	assert.Len(t, c.sources, 1)
+	assert.Equal(t, p, c.sources[0].p)
 }
 
 func Test_WithEnv(t *testing.T) {
 	p := parsers.NewEnvParserWithPrefix("")
 	c := New(WithEnv(""))
 
-	assert.Len(t, c.parsers, 1)
-	assert.Equal(t, p, c.parsers[0])
+	assert.Len(t, c.sources, 1)
+	assert.Equal(t, p, c.sources[0].p)
 }
 
 func Test_WithFile(t *testing.T) {
@@ -30,8 +36,8 @@ func Test_WithFile(t *testing.T) {
 	p := parsers.NewFileParser(fp, "=")
 	c := New(WithFile(fp, "="))
 
-	assert.Len(t, c.parsers, 1)
-	assert.Equal(t, p, c.parsers[0])
+	assert.Len(t, c.sources, 1)
+	assert.Equal(t, p, c.sources[0].p)
 }
 
 func Test_WithKubernetesVolume(t *testing.T) {
@@ -39,8 +45,8 @@ func Test_WithKubernetesVolume(t *testing.T) {
 	p := parsers.NewKubernetesVolumeParser(vp)
 	c := New(WithKubernetesVolume(vp))
 
-	assert.Len(t, c.parsers, 1)
-	assert.Equal(t, p, c.parsers[0])
+	assert.Len(t, c.sources, 1)
+	assert.Equal(t, p, c.sources[0].p)
 }
 
 func Test_WithURL(t *testing.T) {
@@ -48,8 +54,25 @@ func Test_WithURL(t *testing.T) {
 	p := parsers.NewRemoteFileParser(u)
 	c := New(WithURL(u))
 
-	assert.Len(t, c.parsers, 1)
-	assert.Equal(t, p, c.parsers[0])
+	assert.Len(t, c.sources, 1)
+	assert.Equal(t, p, c.sources[0].p)
+}
+
+func Test_WithParserOptions(t *testing.T) {
+	u := &url.URL{}
+	c := New(
+		WithFile("/tmp/path", "="),
+		WithParserOptions(WithURL(u), RefreshInterval(time.Minute), MaxStaleness(time.Hour)),
+		WithParserOptions(WithEnv("APP_"), AutoWatch()))
+	defer c.Close()
+
+	assert.Len(t, c.sources, 3)
+	assert.Equal(t, time.Duration(0), c.sources[0].refresh)
+	assert.Equal(t, parsers.NewRemoteFileParser(u), c.sources[1].p)
+	assert.Equal(t, time.Minute, c.sources[1].refresh)
+	assert.Equal(t, time.Hour, c.sources[1].maxStale)
+	assert.False(t, c.sources[1].autoWatch)
+	assert.True(t, c.sources[2].autoWatch)
 }
 
 func Test_WithValue(t *testing.T) {
@@ -57,8 +80,8 @@ func Test_WithValue(t *testing.T) {
 	p := parsers.NewKeyValueParser(r, parsers.WithKeyValueSeparator("="))
 	c := New(WithValue("key", "value"))
 
-	assert.Len(t, c.parsers, 1)
-	assert.Equal(t, p, c.parsers[0])
+	assert.Len(t, c.sources, 1)
+	assert.Equal(t, p, c.sources[0].p)
 }
 
 func Test_WithWatch(t *testing.T) {
@@ -66,3 +89,145 @@ func Test_WithWatch(t *testing.T) {
 
 	assert.NotNil(t, c.watch)
 }
+
+func Test_WithTagName(t *testing.T) {
+	m := make(map[string]interface{})
+	m["key"] = "value"
+
+	c := New(
+		WithParser(newFakeParser(m)),
+		WithTagName("env"))
+
+	var result struct {
+		Key   string `env:"key"`
+		Other string `config:"key"`
+	}
+	c.Bind(&result)
+
+	assert.Equal(t, "value", result.Key)
+	assert.Empty(t, result.Other)
+}
+
+func Test_WithCompatibleTagNames(t *testing.T) {
+	m := make(map[string]interface{})
+	m["config_key"] = "a"
+	m["env_key"] = "b"
+	m["json_key"] = "c"
+	m["yaml_key"] = "d"
+
+	c := New(
+		WithParser(newFakeParser(m)),
+		WithCompatibleTagNames())
+
+	var result struct {
+		Config  string `config:"config_key" json:"env_key"`
+		Env     string `env:"env_key"`
+		JSON    string `json:"json_key,omitempty"`
+		YAML    string `yaml:"yaml_key"`
+		Skipped string `json:"-"`
+	}
+	c.Bind(&result)
+
+	assert.Equal(t, "a", result.Config)
+	assert.Equal(t, "b", result.Env)
+	assert.Equal(t, "c", result.JSON)
+	assert.Equal(t, "d", result.YAML)
+	assert.Empty(t, result.Skipped)
+}
+
+func Test_WithCompatibleTagNames_EmptyName(t *testing.T) {
+	m := make(map[string]interface{})
+	m["port"] = "8080"
+	m["host"] = "localhost"
+
+	c := New(
+		WithParser(newFakeParser(m)),
+		WithCompatibleTagNames())
+
+	var result struct {
+		Port    string `json:",omitempty" yaml:"port"`
+		Skipped string `json:"-" yaml:"host"`
+	}
+	c.Bind(&result)
+
+	assert.Equal(t, "8080", result.Port)
+	assert.Empty(t, result.Skipped)
+}
+
+func Test_WithReloadDebounce(t *testing.T) {
+	c := New(
+		WithReloadDebounce(time.Second),
+		WithMinReloadInterval(time.Minute))
+
+	assert.Equal(t, time.Second, c.reloads.debounce)
+	assert.Equal(t, time.Minute, c.reloads.minInterval)
+}
+
+func Test_WithFile_AutoWatch(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+
+	c := New(
+		WithFile(fp, "=", AutoWatch()),
+		WithFile(filepath.Join(dir, "other.conf"), "="))
+	defer c.Close()
+
+	assert.NotNil(t, c.watch)
+	assert.Len(t, c.watch.targets, 1)
+	assert.Contains(t, c.watch.targets, fp)
+}
+
+func Test_WithAutoWatch(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	vp := filepath.Join(dir, "volume")
+
+	c := New(
+		WithFile(fp, "="),
+		WithKubernetesVolume(vp),
+		WithEnv(),
+		WithAutoWatch())
+	defer c.Close()
+
+	assert.NotNil(t, c.watch)
+	assert.Len(t, c.watch.targets, 2)
+	assert.Contains(t, c.watch.targets, fp)
+	assert.Contains(t, c.watch.targets, vp)
+}
+
+func Test_AutoWatch_Reload(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	writeFile(t, fp, "binder_key=value1")
+
+	c := New(WithFile(fp, "=", AutoWatch()))
+	defer c.Close()
+
+	w := BindAtomic[fakeBinder3](c)
+	assert.Equal(t, "value1", w.Load().ValueField)
+
+	writeFile(t, fp, "binder_key=value2")
+
+	assert.Eventually(t, func() bool {
+		return w.Load().ValueField == "value2"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func Test_WithSignalReload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported on windows")
+	}
+
+	c := New(WithSignalReload())
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := c.Changes(ctx)
+
+	p, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+	assert.NoError(t, p.Signal(syscall.SIGHUP))
+
+	assert.Equal(t, "signal:hangup", receiveBatch(t, ch).Trigger)
+}
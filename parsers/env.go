@@ -57,3 +57,8 @@ func (p *EnvParser) Parse() (map[string]interface{}, error) {
 
 	return values, nil
 }
+
+// String returns the name of the parser.
+func (p *EnvParser) String() string {
+	return "env"
+}
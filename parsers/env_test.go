@@ -42,3 +42,9 @@ func Test_Env_Without_Prefix(t *testing.T) {
 
 	assert.Equal(t, expected, values[key])
 }
+
+func Test_Env_String(t *testing.T) {
+	p := NewEnvParser()
+
+	assert.Equal(t, "env", p.String())
+}
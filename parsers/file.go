@@ -29,3 +29,15 @@ func (p *FileParser) Parse() (map[string]interface{}, error) {
 	kvp := NewKeyValueParser(h, WithKeyValueSeparator(p.sep))
 	return kvp.Parse()
 }
+
+// String returns the name of the parser,
+// including the path of the backing file.
+func (p *FileParser) String() string {
+	return "file:" + p.fp
+}
+
+// WatchPaths returns the path of the backing file,
+// to be watched for changes.
+func (p *FileParser) WatchPaths() []string {
+	return []string{p.fp}
+}
@@ -17,3 +17,7 @@ func (p *FlagParser) Parse() (map[string]interface{}, error) {
 
 	return result, nil
 }
+
+func (p *FlagParser) String() string {
+	return "flags"
+}
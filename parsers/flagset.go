@@ -21,3 +21,7 @@ func (p *FlagSetParser) Parse() (map[string]interface{}, error) {
 
 	return result, nil
 }
+
+func (p *FlagSetParser) String() string {
+	return "flagset"
+}
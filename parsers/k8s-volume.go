@@ -36,3 +36,11 @@ func (p *KubernetesVolumeParser) Parse() (map[string]interface{}, error) {
 
 	return values, nil
 }
+
+func (p *KubernetesVolumeParser) String() string {
+	return "kubernetes:" + p.p
+}
+
+func (p *KubernetesVolumeParser) WatchPaths() []string {
+	return []string{p.p}
+}
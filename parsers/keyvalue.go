@@ -49,3 +49,7 @@ func (p *KeyValueParser) Parse() (map[string]interface{}, error) {
 
 	return result, nil
 }
+
+func (p *KeyValueParser) String() string {
+	return "keyvalue"
+}
@@ -71,3 +71,7 @@ func (p *RemoteFileParser) Parse() (map[string]interface{}, error) {
 	kvp := NewKeyValueParser(resp.Body, WithKeyValueSeparator(p.sep))
 	return kvp.Parse()
 }
+
+func (p *RemoteFileParser) String() string {
+	return "url:" + p.u.Redacted()
+}
@@ -0,0 +1,200 @@
+package binder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const defaultPollInterval = 2 * time.Second
+
+// pollWatcher watches paths by comparing the
+// modification time, size and content hash of
+// every watched file at an interval, for file
+// systems where fsnotify events aren't delivered,
+// such as NFS and some FUSE mounts.
+type pollWatcher struct {
+	tracked
+	interval time.Duration
+	errfn    func(error)
+
+	m       sync.Mutex
+	targets map[string]*pollTarget
+}
+
+type pollTarget struct {
+	opts   watchOptions
+	exists bool
+	sum    [sha256.Size]byte
+}
+
+func newPollWatcher(interval time.Duration, errfn func(error)) *pollWatcher {
+	return &pollWatcher{
+		interval: interval,
+		errfn:    errfn,
+		targets:  make(map[string]*pollTarget),
+	}
+}
+
+// Start implements Trigger, and polls every
+// watched path until ctx is done.
+func (pw *pollWatcher) Start(ctx context.Context, reload func(reason string)) error {
+	pw.spawn(func() { pw.run(ctx, reload) })
+
+	return nil
+}
+
+// Add watches a file or a directory, which
+// doesn't need to exist yet.
+func (pw *pollWatcher) Add(path string, opts ...WatchOption) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	t := &pollTarget{}
+	for _, opt := range opts {
+		opt(&t.opts)
+	}
+
+	t.exists, t.sum, err = t.state(path)
+
+	pw.m.Lock()
+	defer pw.m.Unlock()
+
+	pw.targets[path] = t
+
+	return err
+}
+
+func (pw *pollWatcher) run(ctx context.Context, reload func(string)) {
+	ticker := time.NewTicker(pw.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, trigger := range pw.poll() {
+				reload(trigger)
+			}
+		}
+	}
+}
+
+// poll returns the watched paths which changed
+// since the last poll. A path which has been
+// removed doesn't trigger a reload until it
+// appears again.
+func (pw *pollWatcher) poll() []string {
+	pw.m.Lock()
+	defer pw.m.Unlock()
+
+	var triggers []string
+	for path, t := range pw.targets {
+		exists, sum, err := t.state(path)
+		if err != nil {
+			pw.errfn(err)
+			continue
+		}
+
+		changed := exists && (!t.exists || sum != t.sum)
+		t.exists, t.sum = exists, sum
+
+		if changed {
+			triggers = append(triggers, path)
+		}
+	}
+
+	return triggers
+}
+
+// state returns whether a watched path exists, and a
+// checksum of the modification time, size and content
+// of the file, or of every file in the directory.
+func (t *pollTarget) state(path string) (bool, [sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, sum, nil
+	}
+	if err != nil {
+		return false, sum, err
+	}
+
+	h := sha256.New()
+	if !fi.IsDir() {
+		if err := hashFile(h, path, fi); err != nil {
+			return false, sum, err
+		}
+
+		copy(sum[:], h.Sum(nil))
+		return true, sum, nil
+	}
+
+	err = t.hashDir(h, path)
+
+	copy(sum[:], h.Sum(nil))
+	return true, sum, err
+}
+
+// hashDir writes the relative path, modification time,
+// size and content of every matching file in a watched
+// directory to h.
+func (t *pollTarget) hashDir(h hash.Hash, dir string) error {
+	return filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if d.IsDir() {
+			if p != dir && !t.opts.recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || !t.opts.match(rel) {
+			return nil
+		}
+
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil
+		}
+
+		_, _ = io.WriteString(h, rel)
+		return hashFile(h, p, fi)
+	})
+}
+
+func hashFile(h hash.Hash, path string, fi os.FileInfo) error {
+	_ = binary.Write(h, binary.LittleEndian, fi.ModTime().UnixNano())
+	_ = binary.Write(h, binary.LittleEndian, fi.Size())
+
+	if fi.IsDir() {
+		return nil
+	}
+
+	f, err := os.Open(path) // #nosec G304 -- path is controlled by user-provided watch path
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	_, err = io.Copy(h, f)
+	return err
+}
@@ -0,0 +1,113 @@
+package binder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PollWatcher_File(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	writeFile(t, fp, "key=1")
+
+	f := &fakeTriggers{}
+	pw := f.pollWatcher(t, 10*time.Millisecond)
+
+	assert.NoError(t, pw.Add(fp))
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, f.count())
+
+	writeFile(t, fp, "key=2")
+	f.waitFor(t, 1)
+
+	f.m.Lock()
+	defer f.m.Unlock()
+	assert.Equal(t, fp, f.triggers[0])
+}
+
+func Test_PollWatcher_SameModTime(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	writeFile(t, fp, "key=1")
+
+	fi, err := os.Stat(fp)
+	assert.NoError(t, err)
+
+	f := &fakeTriggers{}
+	pw := f.pollWatcher(t, 10*time.Millisecond)
+
+	assert.NoError(t, pw.Add(fp))
+
+	writeFile(t, fp, "key=2")
+	assert.NoError(t, os.Chtimes(fp, fi.ModTime(), fi.ModTime()))
+	f.waitFor(t, 1)
+}
+
+func Test_PollWatcher_Missing(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "conf", "values.conf")
+
+	f := &fakeTriggers{}
+	pw := f.pollWatcher(t, 10*time.Millisecond)
+
+	assert.NoError(t, pw.Add(fp))
+
+	assert.NoError(t, os.MkdirAll(filepath.Dir(fp), 0o700))
+	writeFile(t, fp, "key=1")
+	f.waitFor(t, 1)
+}
+
+func Test_PollWatcher_Directory(t *testing.T) {
+	dir := t.TempDir()
+
+	f := &fakeTriggers{}
+	pw := f.pollWatcher(t, 10*time.Millisecond)
+
+	assert.NoError(t, pw.Add(dir, WatchRecursive(), WatchInclude("**/*.conf")))
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "a"), 0o700))
+	writeFile(t, filepath.Join(dir, "a", "other.txt"), "x")
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, f.count())
+
+	writeFile(t, filepath.Join(dir, "a", "values.conf"), "key=1")
+	f.waitFor(t, 1)
+}
+
+func Test_WithPollInterval(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	writeFile(t, fp, "binder_key=value1")
+
+	c := New(
+		WithFile(fp, "="),
+		WithWatch(t.TempDir()),
+		WithWatch(fp, WatchPolling()),
+		WithPollInterval(10*time.Millisecond))
+	defer c.Close()
+
+	assert.NotNil(t, c.watch)
+	assert.Equal(t, 10*time.Millisecond, c.poll.interval)
+
+	w := BindAtomic[fakeBinder3](c)
+	writeFile(t, fp, "binder_key=value2")
+
+	assert.Eventually(t, func() bool {
+		return w.Load().ValueField == "value2"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func Test_WatchPolling(t *testing.T) {
+	c := New(
+		WithWatch(t.TempDir()),
+		WithWatch(t.TempDir(), WatchPolling()))
+	defer c.Close()
+
+	assert.NotNil(t, c.watch)
+	assert.NotNil(t, c.poll)
+}
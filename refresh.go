@@ -0,0 +1,53 @@
+package binder
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// startRefresh starts re-reading every source which
+// has a refresh interval, grouping sources which
+// share the same interval.
+func (c *Config) startRefresh(sources []*source) {
+	groups := make(map[time.Duration][]*source)
+	var intervals []time.Duration
+
+	for _, s := range sources {
+		d := s.refresh
+		if d == 0 {
+			d = c.refreshInterval
+		}
+		if d <= 0 {
+			continue
+		}
+
+		if _, ok := groups[d]; !ok {
+			intervals = append(intervals, d)
+		}
+		groups[d] = append(groups[d], s)
+	}
+
+	for _, d := range intervals {
+		sources := groups[d]
+		c.spawn(func() { c.refreshEvery(d, sources) })
+	}
+}
+
+// refreshEvery re-reads sources at an interval with
+// up to 10% jitter, until the Config is closed.
+func (c *Config) refreshEvery(d time.Duration, sources []*source) {
+	for {
+		t := time.NewTimer(d + rand.N(d/10+1))
+
+		select {
+		case <-c.ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+
+		_ = c.reloads.do(c.ctx, func() {
+			c.refresh(sources, TriggerTimer)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package binder
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingParser struct {
+	m     sync.Mutex
+	calls int
+	value string
+}
+
+func (p *countingParser) Parse() (map[string]interface{}, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.calls++
+	return map[string]interface{}{"binder_key": p.value}, nil
+}
+
+func (p *countingParser) set(value string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.value = value
+}
+
+func (p *countingParser) count() int {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return p.calls
+}
+
+func Test_RefreshInterval(t *testing.T) {
+	refreshed := &countingParser{value: "value1"}
+	static := &countingParser{value: "static"}
+
+	c := New(
+		WithParser(static),
+		WithParser(refreshed, RefreshInterval(10*time.Millisecond)))
+	defer c.Close()
+
+	w := BindAtomic[fakeBinder3](c)
+	assert.Equal(t, "value1", w.Load().ValueField)
+
+	refreshed.set("value2")
+
+	assert.Eventually(t, func() bool {
+		return w.Load().ValueField == "value2"
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, static.count())
+}
+
+func Test_RefreshInterval_Unchanged(t *testing.T) {
+	p := &countingParser{value: "value1"}
+
+	c := New(WithParser(p, RefreshInterval(10*time.Millisecond)))
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := c.Changes(ctx)
+
+	var b fakeBinder
+	c.Bind(&b)
+
+	assert.Eventually(t, func() bool {
+		return p.count() > 3
+	}, 2*time.Second, 10*time.Millisecond)
+
+	select {
+	case batch := <-ch:
+		t.Fatalf("unexpected reload: %v", batch)
+	default:
+	}
+
+	p.set("value2")
+	batch := receiveBatch(t, ch)
+	assert.Equal(t, TriggerTimer, batch.Trigger)
+	assert.Len(t, batch.Modified, 1)
+}
+
+func Test_WithRefreshInterval(t *testing.T) {
+	p := &countingParser{value: "value1"}
+
+	c := New(
+		WithParser(p),
+		WithRefreshInterval(10*time.Millisecond))
+	defer c.Close()
+
+	v := String(c, "binder_key", "", "")
+	p.set("value2")
+
+	assert.Eventually(t, func() bool {
+		return v.Load() == "value2"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func Test_Use_WhileRefreshing(t *testing.T) {
+	p := &fakeTickingParser{}
+
+	c := New(
+		WithParser(p),
+		WithRefreshInterval(time.Millisecond))
+	defer c.Close()
+
+	for i := 0; i < 100; i++ {
+		c.Use(&countingParser{value: "value"})
+		time.Sleep(100 * time.Microsecond)
+	}
+
+	_, err := c.Reload(context.Background())
+	assert.NoError(t, err)
+	assert.Greater(t, p.n.Load(), int64(2))
+}
@@ -0,0 +1,128 @@
+package binder
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// reloader coalesces reload requests, and makes
+// sure that reloads never overlap.
+type reloader struct {
+	fn          func(trigger string)
+	debounce    time.Duration
+	minInterval time.Duration
+
+	m       sync.Mutex
+	timer   *time.Timer
+	trigger string
+	last    time.Time
+	stopped bool
+
+	// run holds a token while a reload is running.
+	run chan struct{}
+}
+
+func newReloader(fn func(trigger string)) *reloader {
+	return &reloader{fn: fn, run: make(chan struct{}, 1)}
+}
+
+// request schedules a reload. Requests arriving within
+// the debounce period of each other are coalesced into
+// a single reload after a quiet period, and reloads are
+// spaced by at least the minimum interval. The trigger
+// of the latest request is passed on to the reload.
+func (r *reloader) request(trigger string) {
+	r.m.Lock()
+	if r.stopped {
+		r.m.Unlock()
+		return
+	}
+	r.trigger = trigger
+
+	delay := r.debounce
+	if wait := r.minInterval - time.Since(r.last); wait > delay {
+		delay = wait
+	}
+
+	if r.timer != nil {
+		r.timer.Reset(delay)
+		r.m.Unlock()
+		return
+	}
+
+	if delay > 0 {
+		r.timer = time.AfterFunc(delay, r.fire)
+		r.m.Unlock()
+		return
+	}
+
+	r.m.Unlock()
+	r.fire()
+}
+
+func (r *reloader) fire() {
+	r.run <- struct{}{}
+	defer func() { <-r.run }()
+
+	r.m.Lock()
+	trigger := r.trigger
+	stopped := r.stopped
+	r.timer = nil
+	r.m.Unlock()
+
+	if stopped {
+		return
+	}
+
+	r.fn(trigger)
+
+	r.m.Lock()
+	r.last = time.Now()
+	r.m.Unlock()
+}
+
+// do runs fn immediately, after any reload which is
+// already running, and counts it as a reload when
+// spacing reloads by the minimum interval. It returns
+// the error of ctx if ctx is done before fn could run,
+// or ErrClosed if the reloader has been stopped.
+func (r *reloader) do(ctx context.Context, fn func()) error {
+	select {
+	case r.run <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-r.run }()
+
+	r.m.Lock()
+	stopped := r.stopped
+	r.m.Unlock()
+
+	if stopped {
+		return ErrClosed
+	}
+
+	fn()
+
+	r.m.Lock()
+	r.last = time.Now()
+	r.m.Unlock()
+
+	return nil
+}
+
+// stop cancels any pending reload, makes every later
+// request a no-op, and waits for a running reload.
+func (r *reloader) stop() {
+	r.m.Lock()
+	r.stopped = true
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	r.m.Unlock()
+
+	r.run <- struct{}{}
+	<-r.run
+}
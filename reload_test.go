@@ -0,0 +1,104 @@
+package binder
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReload struct {
+	m        sync.Mutex
+	triggers []string
+	times    []time.Time
+}
+
+func (f *fakeReload) fn(trigger string) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.triggers = append(f.triggers, trigger)
+	f.times = append(f.times, time.Now())
+}
+
+func (f *fakeReload) calls() []string {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	return append([]string(nil), f.triggers...)
+}
+
+func Test_Reloader_Immediate(t *testing.T) {
+	f := &fakeReload{}
+	r := newReloader(f.fn)
+
+	r.request("a")
+	r.request("b")
+
+	assert.Equal(t, []string{"a", "b"}, f.calls())
+}
+
+func Test_Reloader_Debounce(t *testing.T) {
+	f := &fakeReload{}
+	r := newReloader(f.fn)
+	r.debounce = 50 * time.Millisecond
+
+	for i := 0; i < 10; i++ {
+		r.request("a")
+	}
+	r.request("b")
+
+	assert.Empty(t, f.calls())
+	assert.Eventually(t, func() bool {
+		return len(f.calls()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, []string{"b"}, f.calls())
+}
+
+func Test_Reloader_MinInterval(t *testing.T) {
+	f := &fakeReload{}
+	r := newReloader(f.fn)
+	r.minInterval = 100 * time.Millisecond
+
+	r.request("a")
+	r.request("b")
+	r.request("c")
+
+	assert.Equal(t, []string{"a"}, f.calls())
+	assert.Eventually(t, func() bool {
+		return len(f.calls()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	assert.Equal(t, []string{"a", "c"}, f.triggers)
+	assert.GreaterOrEqual(t, f.times[1].Sub(f.times[0]), 100*time.Millisecond)
+}
+
+func Test_Reloader_Serialized(t *testing.T) {
+	var running, overlaps int32
+	r := newReloader(func(string) {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.AddInt32(&overlaps, 1)
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.request("a")
+		}()
+	}
+	wg.Wait()
+
+	assert.Zero(t, atomic.LoadInt32(&overlaps))
+}
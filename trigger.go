@@ -0,0 +1,209 @@
+package binder
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// Trigger values passed as reason to a reload
+// by the built-in triggers and Config.Reload, next
+// to the path of a changed file.
+const (
+	TriggerTimer   = "timer"
+	TriggerChannel = "channel"
+	TriggerManual  = "manual"
+)
+
+// Trigger is an interface which defines the
+// minimum requirement to implement a custom
+// source of reloads, e.g. for remote or database
+// backed configuration parsers.
+//
+// Start is called once when the Config is created,
+// and should call reload whenever configuration
+// values should be re-read, until ctx is done. The
+// reason is passed on as Trigger of a ChangeBatch.
+type Trigger interface {
+	Start(ctx context.Context, reload func(reason string)) error
+}
+
+// errorReporter is implemented by triggers which
+// report errors after they are started.
+type errorReporter interface {
+	reportErrors(errfn func(error))
+}
+
+// tracker is implemented by triggers which run their
+// goroutines through spawn, so that closing a Config
+// waits for them to exit.
+type tracker interface {
+	track(spawn func(fn func()))
+}
+
+// tracked implements tracker, and is embedded
+// by the built-in triggers.
+type tracked struct {
+	spawnfn func(fn func())
+}
+
+func (t *tracked) track(spawn func(fn func())) {
+	t.spawnfn = spawn
+}
+
+// spawn runs fn in a goroutine, which is waited for
+// when the tracking Config is closed.
+func (t *tracked) spawn(fn func()) {
+	if t.spawnfn != nil {
+		t.spawnfn(fn)
+		return
+	}
+
+	go fn()
+}
+
+type watchTrigger struct {
+	tracked
+	path     string
+	opts     []WatchOption
+	interval time.Duration
+	errfn    func(error)
+}
+
+// NewFileTrigger returns a Trigger which reloads when
+// the file or directory at path changes, using file
+// system events. See Config.Watch for details.
+func NewFileTrigger(path string, opts ...WatchOption) Trigger {
+	return &watchTrigger{path: path, opts: opts, errfn: func(error) {}}
+}
+
+// NewPollTrigger returns a Trigger which reloads when
+// the file or directory at path changes, by polling
+// it at the specified interval.
+func NewPollTrigger(interval time.Duration, path string, opts ...WatchOption) Trigger {
+	return &watchTrigger{path: path, opts: opts, interval: interval, errfn: func(error) {}}
+}
+
+func (t *watchTrigger) reportErrors(errfn func(error)) {
+	t.errfn = errfn
+}
+
+func (t *watchTrigger) Start(ctx context.Context, reload func(reason string)) error {
+	if t.interval > 0 {
+		pw := newPollWatcher(t.interval, t.errfn)
+		pw.spawnfn = t.spawnfn
+		if err := pw.Add(t.path, t.opts...); err != nil {
+			return err
+		}
+		return pw.Start(ctx, reload)
+	}
+
+	fw, err := newFileWatcher(t.errfn)
+	if err != nil {
+		return err
+	}
+	fw.spawnfn = t.spawnfn
+
+	if err := fw.Add(t.path, t.opts...); err != nil {
+		_ = fw.Close()
+		return err
+	}
+
+	return fw.Start(ctx, reload)
+}
+
+type timerTrigger struct {
+	tracked
+	interval time.Duration
+}
+
+// NewTimerTrigger returns a Trigger which reloads
+// at the specified interval, with reason TriggerTimer.
+func NewTimerTrigger(interval time.Duration) Trigger {
+	return &timerTrigger{interval: interval}
+}
+
+func (t *timerTrigger) Start(ctx context.Context, reload func(reason string)) error {
+	t.spawn(func() {
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reload(TriggerTimer)
+			}
+		}
+	})
+
+	return nil
+}
+
+type signalTrigger struct {
+	tracked
+	sigs []os.Signal
+}
+
+// NewSignalTrigger returns a Trigger which reloads
+// when the process receives any of the specified
+// signals, with the name of the signal as reason,
+// e.g. `signal:hangup`.
+func NewSignalTrigger(sigs ...os.Signal) Trigger {
+	return &signalTrigger{sigs: sigs}
+}
+
+func (t *signalTrigger) Start(ctx context.Context, reload func(reason string)) error {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, t.sigs...)
+
+	t.spawn(func() {
+		defer signal.Stop(ch)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-ch:
+				reload("signal:" + sig.String())
+			}
+		}
+	})
+
+	return nil
+}
+
+type chanTrigger struct {
+	tracked
+	ch <-chan string
+}
+
+// NewChanTrigger returns a Trigger which reloads
+// for every value received on ch, using the value
+// as reason, or TriggerChannel if it's empty.
+func NewChanTrigger(ch <-chan string) Trigger {
+	return &chanTrigger{ch: ch}
+}
+
+func (t *chanTrigger) Start(ctx context.Context, reload func(reason string)) error {
+	t.spawn(func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case reason, ok := <-t.ch:
+				if !ok {
+					return
+				}
+				if reason == "" {
+					reason = TriggerChannel
+				}
+				reload(reason)
+			}
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package binder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receiveBatch(t *testing.T, ch <-chan ChangeBatch) ChangeBatch {
+	t.Helper()
+
+	select {
+	case batch := <-ch:
+		return batch
+	case <-time.After(2 * time.Second):
+		t.Fatal("no reload")
+		return ChangeBatch{}
+	}
+}
+
+func Test_ChanTrigger(t *testing.T) {
+	reloads := make(chan string)
+	c := New(WithTrigger(NewChanTrigger(reloads)))
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := c.Changes(ctx)
+
+	reloads <- "database"
+	assert.Equal(t, "database", receiveBatch(t, ch).Trigger)
+
+	reloads <- ""
+	assert.Equal(t, TriggerChannel, receiveBatch(t, ch).Trigger)
+}
+
+func Test_TimerTrigger(t *testing.T) {
+	c := New(WithTrigger(NewTimerTrigger(10 * time.Millisecond)))
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := c.Changes(ctx)
+
+	assert.Equal(t, TriggerTimer, receiveBatch(t, ch).Trigger)
+}
+
+func Test_SignalTrigger(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not supported on windows")
+	}
+
+	c := New(WithTrigger(NewSignalTrigger(syscall.SIGHUP)))
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := c.Changes(ctx)
+
+	p, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+	assert.NoError(t, p.Signal(syscall.SIGHUP))
+
+	assert.Equal(t, "signal:hangup", receiveBatch(t, ch).Trigger)
+}
+
+func Test_FileTrigger(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	writeFile(t, fp, "key=1")
+
+	c := New(WithTrigger(NewFileTrigger(fp)))
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := c.Changes(ctx)
+
+	writeFile(t, fp, "key=2")
+	assert.Equal(t, fp, receiveBatch(t, ch).Trigger)
+}
+
+func Test_PollTrigger(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	writeFile(t, fp, "key=1")
+
+	c := New(WithTrigger(NewPollTrigger(10*time.Millisecond, fp)))
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := c.Changes(ctx)
+
+	writeFile(t, fp, "key=2")
+	assert.Equal(t, fp, receiveBatch(t, ch).Trigger)
+}
+
+type fakeTrigger struct {
+	err error
+}
+
+func (f *fakeTrigger) Start(context.Context, func(string)) error {
+	return f.err
+}
+
+func Test_Trigger_Error(t *testing.T) {
+	c := New(WithTrigger(&fakeTrigger{assert.AnError}))
+
+	assert.Equal(t, assert.AnError, <-c.Errors())
+}
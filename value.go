@@ -3,23 +3,199 @@ package binder
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Values is a collection of configuration values.
 type Values struct {
-	m map[string]*Value
+	m    map[string]*Value
+	keys []string
+	mu   sync.Mutex
+	idx  map[BindMode]map[string]string
 }
 
-func (v *Values) getIgnoreCase(key string) *Value {
-	for k, v := range v.m {
-		if strings.EqualFold(key, k) {
-			return v
+func newValues(m map[string]*Value, keys []string) *Values {
+	return &Values{m: m, keys: keys}
+}
+
+// ordered returns all keys in order of precedence,
+// where a later key takes precedence over an earlier
+// key which normalizes to the same form.
+func (v *Values) ordered() []string {
+	if v.keys != nil {
+		return v.keys
+	}
+
+	keys := make([]string, 0, len(v.m))
+	for k := range v.m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// index returns a lookup from normalized key to
+// key for the specified mode, building it once.
+func (v *Values) index(mode BindMode) map[string]string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if idx, ok := v.idx[mode]; ok {
+		return idx
+	}
+
+	idx := make(map[string]string, len(v.m))
+	for _, k := range v.ordered() {
+		idx[mode.normalize(k)] = k
+	}
+
+	if v.idx == nil {
+		v.idx = make(map[BindMode]map[string]string)
+	}
+	v.idx[mode] = idx
+
+	return idx
+}
+
+// collisions returns every group of keys which
+// normalize to the same form for the specified
+// mode, ordered by precedence.
+func (v *Values) collisions(mode BindMode) [][]string {
+	groups := make(map[string][]string)
+	var normalized []string
+
+	for _, k := range v.ordered() {
+		nk := mode.normalize(k)
+		if _, ok := groups[nk]; !ok {
+			normalized = append(normalized, nk)
 		}
+		groups[nk] = append(groups[nk], k)
+	}
+
+	var result [][]string
+	for _, nk := range normalized {
+		if len(groups[nk]) > 1 {
+			result = append(result, groups[nk])
+		}
+	}
+
+	return result
+}
+
+// lookup returns the value matching key, preferring
+// an exact match over a normalized match.
+func (v *Values) lookup(key string, op BindMode) *Value {
+	if value, ok := v.m[key]; ok {
+		return value
+	}
+
+	mode := op.fuzzy()
+	if mode == 0 {
+		return nil
+	}
+
+	k, ok := v.index(mode)[mode.normalize(key)]
+	if !ok {
+		return nil
 	}
 
-	return nil
+	return v.m[k]
+}
+
+// Sub returns the subset of values whose keys start
+// with the specified prefix followed by a dot, with
+// the prefix and dot removed from every key.
+func (v *Values) Sub(prefix string) *Values {
+	return v.sub(prefix, ModeStrict)
+}
+
+// sub returns the subset of values whose keys start
+// with the specified prefix, matched using the flags
+// in op.
+func (v *Values) sub(prefix string, op BindMode) *Values {
+	mode := op.fuzzy()
+	np := mode.normalize(prefix)
+
+	m := make(map[string]*Value)
+	var keys []string
+
+	for _, k := range v.ordered() {
+		rest, ok := trimKeyPrefix(k, np, mode)
+		if !ok {
+			continue
+		}
+
+		if _, ok := m[rest]; ok {
+			keys = removeKey(keys, rest)
+		}
+
+		m[rest] = v.m[k]
+		keys = append(keys, rest)
+	}
+
+	return newValues(m, keys)
+}
+
+// trimKeyPrefix removes a normalized prefix np and the
+// separator following it from key. A dot always separates
+// keys, while `_` and `-` separate keys only when using
+// ModeIgnoreSeparators.
+func trimKeyPrefix(key string, np string, mode BindMode) (string, bool) {
+	for i := 0; i < len(key); i++ {
+		if !isKeySeparator(key[i], mode) || mode.normalize(key[:i]) != np {
+			continue
+		}
+
+		rest := key[i+1:]
+		if mode.has(ModeIgnoreSeparators) {
+			rest = strings.TrimLeft(rest, "_-.")
+		}
+
+		if rest != "" {
+			return rest, true
+		}
+	}
+
+	return "", false
+}
+
+func isKeySeparator(b byte, mode BindMode) bool {
+	switch b {
+	case '.':
+		return true
+	case '_', '-':
+		return mode.has(ModeIgnoreSeparators)
+	}
+
+	return false
+}
+
+func removeKey(keys []string, key string) []string {
+	for i, k := range keys {
+		if k == key {
+			return append(keys[:i], keys[i+1:]...)
+		}
+	}
+
+	return keys
+}
+
+// joinKey joins a key prefix and a key with a dot.
+func joinKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	if key == "" {
+		return prefix
+	}
+
+	return prefix + "." + key
 }
 
 // Get returns the value matching the specified key,
@@ -30,12 +206,16 @@ func (v *Values) Get(key string) (string, bool) {
 	return v.m[key].String()
 }
 
-func (v *Values) getString(key string, op BindMode) (string, bool) {
-	if op.has(ModeStrict) {
-		return v.Get(key)
-	}
+// Lookup returns the value matching the specified
+// key, or nil if no such key was found. The value
+// tells which parser supplied it, and whether it is
+// stale.
+func (v *Values) Lookup(key string) *Value {
+	return v.m[key]
+}
 
-	return v.getIgnoreCase(key).String()
+func (v *Values) getString(key string, op BindMode) (string, bool) {
+	return v.lookup(key, op).String()
 }
 
 // GetStrings returns the value matching the specified
@@ -47,14 +227,6 @@ func (v *Values) GetStrings(key string) ([]string, bool) {
 	return v.m[key].StringArray()
 }
 
-func (v *Values) getStrings(key string, op BindMode) ([]string, bool) {
-	if op.has(ModeStrict) {
-		return v.GetStrings(key)
-	}
-
-	return v.getIgnoreCase(key).StringArray()
-}
-
 // GetInt returns the value matching the specified key,
 // as an integer. It returns true as second return
 // value if the specified key exist, or false
@@ -64,11 +236,7 @@ func (v *Values) GetInt(key string) (int, bool) {
 }
 
 func (v *Values) getInt(key string, op BindMode) (int, bool) {
-	if op.has(ModeStrict) {
-		return v.GetInt(key)
-	}
-
-	return v.getIgnoreCase(key).Int()
+	return v.lookup(key, op).Int()
 }
 
 // GetFloat returns the value matching the specified key,
@@ -80,11 +248,7 @@ func (v *Values) GetFloat(key string) (float64, bool) {
 }
 
 func (v *Values) getFloat(key string, op BindMode) (float64, bool) {
-	if op.has(ModeStrict) {
-		return v.GetFloat(key)
-	}
-
-	return v.getIgnoreCase(key).Float()
+	return v.lookup(key, op).Float()
 }
 
 // GetBool returns the value matching the specified key,
@@ -96,16 +260,40 @@ func (v *Values) GetBool(key string) (bool, bool) {
 }
 
 func (v *Values) getBool(key string, op BindMode) (bool, bool) {
-	if op.has(ModeStrict) {
-		return v.GetBool(key)
-	}
-
-	return v.getIgnoreCase(key).Bool()
+	return v.lookup(key, op).Bool()
 }
 
 // Value wraps a configuration value.
 type Value struct {
-	v interface{}
+	v     interface{}
+	src   string
+	stale bool
+}
+
+// Source returns the name of the parser which
+// supplied the value.
+func (c *Value) Source() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.src
+}
+
+// Stale returns true if the value is from the last
+// successful parse of a parser which currently fails.
+func (c *Value) Stale() bool {
+	return c != nil && c.stale
+}
+
+// equal returns true if both values are the same,
+// and either both or neither are stale.
+func (c *Value) equal(other *Value) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+
+	return c.stale == other.stale && reflect.DeepEqual(c.v, other.v)
 }
 
 // String returns a configuration value in string format.
@@ -121,16 +309,26 @@ func (c *Value) String() (string, bool) {
 	return fmt.Sprintf("%v", c.v), true
 }
 
-// StringArray returns a configuration collection of strings.
+// StringArray returns a configuration collection of strings,
+// and returns true as second return value if the value is
+// a slice or an array of strings - otherwise it returns false.
 func (c *Value) StringArray() ([]string, bool) {
 	if c == nil {
 		return nil, false
 	}
 
 	o := reflect.ValueOf(c.v)
+	if o.Kind() != reflect.Slice && o.Kind() != reflect.Array {
+		return nil, false
+	}
+
 	var s []string
 	for i := 0; i < o.Len(); i++ {
-		s = append(s, o.Index(i).Interface().(string))
+		e, ok := o.Index(i).Interface().(string)
+		if !ok {
+			return nil, false
+		}
+		s = append(s, e)
 	}
 
 	return s, true
@@ -207,3 +405,30 @@ func (c *Value) Bool() (bool, bool) {
 
 	return false, false
 }
+
+// Duration returns a configuration value as a time.Duration,
+// and return true as second return value if the value could
+// be returned as a duration - otherwise it returns false.
+// Integers are interpreted as a number of nanoseconds, and
+// strings are parsed using time.ParseDuration.
+func (c *Value) Duration() (time.Duration, bool) {
+	if c == nil {
+		return 0, false
+	}
+
+	if d, ok := c.v.(time.Duration); ok {
+		return d, true
+	}
+	if i, ok := c.v.(int); ok {
+		return time.Duration(i), true
+	}
+
+	if s, ok := c.v.(string); ok {
+		d, err := time.ParseDuration(s)
+		if err == nil {
+			return d, true
+		}
+	}
+
+	return 0, false
+}
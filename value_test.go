@@ -2,6 +2,7 @@ package binder
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -10,9 +11,7 @@ func Test_Values_Get(t *testing.T) {
 	m := make(map[string]*Value)
 	m["key"] = &Value{v: "value"}
 
-	v := &Values{
-		m,
-	}
+	v := newValues(m, nil)
 
 	value, ok := v.Get("key")
 	assert.True(t, ok)
@@ -23,22 +22,32 @@ func Test_Values_GetStrings(t *testing.T) {
 	m := make(map[string]*Value)
 	m["key"] = &Value{v: []string{"val1", "val2"}}
 
-	v := &Values{
-		m,
-	}
+	v := newValues(m, nil)
 
 	values, ok := v.GetStrings("key")
 	assert.True(t, ok)
 	assert.EqualValues(t, []string{"val1", "val2"}, values)
 }
 
+func Test_Values_GetStrings_Fail(t *testing.T) {
+	m := make(map[string]*Value)
+	m["string"] = &Value{v: "a,b"}
+	m["ints"] = &Value{v: []int{1, 2}}
+
+	v := newValues(m, nil)
+
+	_, ok := v.GetStrings("string")
+	assert.False(t, ok)
+
+	_, ok = v.GetStrings("ints")
+	assert.False(t, ok)
+}
+
 func Test_Values_GetInt(t *testing.T) {
 	m := make(map[string]*Value)
 	m["key"] = &Value{v: 100}
 
-	v := &Values{
-		m,
-	}
+	v := newValues(m, nil)
 
 	value, ok := v.GetInt("key")
 	assert.True(t, ok)
@@ -49,9 +58,7 @@ func Test_Values_GetInt_Fail(t *testing.T) {
 	m := make(map[string]*Value)
 	m["key"] = &Value{v: "x"}
 
-	v := &Values{
-		m,
-	}
+	v := newValues(m, nil)
 
 	_, ok := v.GetInt("key")
 	assert.False(t, ok)
@@ -61,9 +68,7 @@ func Test_Values_GetFloat(t *testing.T) {
 	m := make(map[string]*Value)
 	m["key"] = &Value{v: 100.01}
 
-	v := &Values{
-		m,
-	}
+	v := newValues(m, nil)
 
 	value, ok := v.GetFloat("key")
 	assert.True(t, ok)
@@ -74,9 +79,7 @@ func Test_Values_GetFloat_Fail(t *testing.T) {
 	m := make(map[string]*Value)
 	m["key"] = &Value{v: "x"}
 
-	v := &Values{
-		m,
-	}
+	v := newValues(m, nil)
 
 	_, ok := v.GetFloat("key")
 	assert.False(t, ok)
@@ -86,9 +89,7 @@ func Test_Values_GetBool(t *testing.T) {
 	m := make(map[string]*Value)
 	m["key"] = &Value{v: true}
 
-	v := &Values{
-		m,
-	}
+	v := newValues(m, nil)
 
 	value, ok := v.GetBool("key")
 	assert.True(t, ok)
@@ -99,10 +100,59 @@ func Test_Values_GetBool_Fail(t *testing.T) {
 	m := make(map[string]*Value)
 	m["key"] = &Value{v: "x"}
 
-	v := &Values{
-		m,
-	}
+	v := newValues(m, nil)
 
 	_, ok := v.GetBool("key")
 	assert.False(t, ok)
 }
+
+func Test_Values_Sub(t *testing.T) {
+	m := make(map[string]*Value)
+	m["db.host"] = &Value{v: "localhost"}
+	m["db"] = &Value{v: "x"}
+	m["dbx.host"] = &Value{v: "x"}
+	m["DB.port"] = &Value{v: "5432"}
+
+	v := newValues(m, nil).Sub("db")
+
+	value, ok := v.Get("host")
+	assert.True(t, ok)
+	assert.Equal(t, "localhost", value)
+
+	_, ok = v.Get("port")
+	assert.False(t, ok)
+	assert.Len(t, v.m, 1)
+}
+
+func Test_Values_Sub_IgnoreCase(t *testing.T) {
+	m := make(map[string]*Value)
+	m["db.host"] = &Value{v: "localhost"}
+	m["DB.port"] = &Value{v: "5432"}
+
+	v := newValues(m, nil).sub("db", ModeIgnoreCase)
+
+	value, ok := v.Get("port")
+	assert.True(t, ok)
+	assert.Equal(t, "5432", value)
+	assert.Len(t, v.m, 2)
+}
+
+func Test_Values_Duration(t *testing.T) {
+	m := make(map[string]*Value)
+	m["string"] = &Value{v: "1m30s"}
+	m["int"] = &Value{v: 100}
+	m["fail"] = &Value{v: "x"}
+
+	v := newValues(m, nil)
+
+	d, ok := v.m["string"].Duration()
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Second, d)
+
+	d, ok = v.m["int"].Duration()
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(100), d)
+
+	_, ok = v.m["fail"].Duration()
+	assert.False(t, ok)
+}
@@ -0,0 +1,131 @@
+package binder
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Var is a configuration variable declared with
+// one of Int, Float64, Bool, String or Duration.
+// Load always returns the value from the latest
+// reload, and is safe for concurrent use.
+type Var[T any] struct {
+	key   string
+	def   T
+	usage string
+	conv  func(*Value) (T, bool)
+	v     atomic.Pointer[T]
+}
+
+// VarInfo describes a configuration variable
+// declared with one of Int, Float64, Bool, String
+// or Duration, and can be used to generate help
+// texts.
+type VarInfo struct {
+	Key     string
+	Default interface{}
+	Usage   string
+}
+
+type liveVar interface {
+	update(values *Values, mode BindMode)
+	info() VarInfo
+}
+
+// Int declares an int configuration variable
+// with the specified key, default value and usage.
+func Int(c *Config, key string, def int, usage string) *Var[int] {
+	return newVar(c, key, def, usage, (*Value).Int)
+}
+
+// Float64 declares a float64 configuration variable
+// with the specified key, default value and usage.
+func Float64(c *Config, key string, def float64, usage string) *Var[float64] {
+	return newVar(c, key, def, usage, (*Value).Float)
+}
+
+// Bool declares a bool configuration variable
+// with the specified key, default value and usage.
+func Bool(c *Config, key string, def bool, usage string) *Var[bool] {
+	return newVar(c, key, def, usage, (*Value).Bool)
+}
+
+// String declares a string configuration variable
+// with the specified key, default value and usage.
+func String(c *Config, key string, def string, usage string) *Var[string] {
+	return newVar(c, key, def, usage, (*Value).String)
+}
+
+// Duration declares a time.Duration configuration
+// variable with the specified key, default value and
+// usage.
+func Duration(c *Config, key string, def time.Duration, usage string) *Var[time.Duration] {
+	return newVar(c, key, def, usage, (*Value).Duration)
+}
+
+func newVar[T any](c *Config, key string, def T, usage string, conv func(*Value) (T, bool)) *Var[T] {
+	v := &Var[T]{
+		key:   joinKey(c.prefix, key),
+		def:   def,
+		usage: usage,
+		conv:  conv,
+	}
+
+	c.current()
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	v.update(c.cache, c.mask)
+	c.vars = append(c.vars, v)
+
+	return v
+}
+
+// Load returns the current value of the variable,
+// or its default value if the key is missing or
+// has the wrong format.
+func (v *Var[T]) Load() T {
+	return *v.v.Load()
+}
+
+// Key returns the configuration key of the variable.
+func (v *Var[T]) Key() string {
+	return v.key
+}
+
+// Usage returns the usage text of the variable.
+func (v *Var[T]) Usage() string {
+	return v.usage
+}
+
+// Default returns the default value of the variable.
+func (v *Var[T]) Default() T {
+	return v.def
+}
+
+func (v *Var[T]) update(values *Values, mode BindMode) {
+	value, ok := v.conv(values.lookup(v.key, mode))
+	if !ok {
+		value = v.def
+	}
+
+	v.v.Store(&value)
+}
+
+func (v *Var[T]) info() VarInfo {
+	return VarInfo{v.key, v.def, v.usage}
+}
+
+// VisitVars calls fn for each configuration variable
+// declared with one of Int, Float64, Bool, String or
+// Duration, in the order they were declared.
+func (c *Config) VisitVars(fn func(VarInfo)) {
+	c.m.Lock()
+	vars := append([]liveVar(nil), c.vars...)
+	c.m.Unlock()
+
+	for _, v := range vars {
+		fn(v.info())
+	}
+}
@@ -0,0 +1,86 @@
+package binder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Vars(t *testing.T) {
+	m := make(map[string]interface{})
+	m["workers"] = "8"
+	m["timeout"] = "5s"
+	m["name"] = "binder"
+	m["enabled"] = "true"
+	m["ratio"] = "0.5"
+
+	c := New(WithParser(newFakeParser(m)))
+
+	workers := Int(c, "workers", 4, "number of workers")
+	timeout := Duration(c, "timeout", time.Second, "request timeout")
+	name := String(c, "name", "", "service name")
+	enabled := Bool(c, "enabled", false, "enable feature")
+	ratio := Float64(c, "ratio", 1, "sampling ratio")
+
+	assert.Equal(t, 8, workers.Load())
+	assert.Equal(t, 5*time.Second, timeout.Load())
+	assert.Equal(t, "binder", name.Load())
+	assert.True(t, enabled.Load())
+	assert.Equal(t, 0.5, ratio.Load())
+}
+
+func Test_Vars_Default(t *testing.T) {
+	m := make(map[string]interface{})
+	m["workers"] = "x"
+
+	c := New(WithParser(newFakeParser(m)))
+
+	workers := Int(c, "workers", 4, "number of workers")
+	timeout := Duration(c, "timeout", time.Second, "request timeout")
+
+	assert.Equal(t, 4, workers.Load())
+	assert.Equal(t, time.Second, timeout.Load())
+}
+
+func Test_Vars_Reload(t *testing.T) {
+	p := &fakeRebindParser{"value1"}
+	c := New(WithParser(p))
+
+	v := String(c, "binder_key", "", "")
+	assert.Equal(t, "value1", v.Load())
+
+	p.value = "value2"
+	c.apply("test")
+
+	assert.Equal(t, "value2", v.Load())
+}
+
+func Test_Vars_Sub(t *testing.T) {
+	m := make(map[string]interface{})
+	m["pool.workers"] = "8"
+
+	c := New(WithParser(newFakeParser(m)))
+
+	workers := Int(c.Sub("pool"), "workers", 4, "")
+
+	assert.Equal(t, 8, workers.Load())
+	assert.Equal(t, "pool.workers", workers.Key())
+}
+
+func Test_VisitVars(t *testing.T) {
+	c := New()
+
+	Int(c, "workers", 4, "number of workers")
+	Duration(c, "timeout", time.Second, "request timeout")
+
+	var infos []VarInfo
+	c.VisitVars(func(info VarInfo) {
+		infos = append(infos, info)
+	})
+
+	assert.Equal(t, []VarInfo{
+		{"workers", 4, "number of workers"},
+		{"timeout", time.Second, "request timeout"},
+	}, infos)
+}
@@ -0,0 +1,450 @@
+package binder
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchOption is passed to WithWatch and
+// Config.Watch, and used through functional
+// parameters.
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	recursive bool
+	include   []string
+	exclude   []string
+	poll      bool
+}
+
+// WatchPolling watches a path by polling it at
+// the interval set with WithPollInterval, instead
+// of relying on file system events.
+func WatchPolling() WatchOption {
+	return func(o *watchOptions) {
+		o.poll = true
+	}
+}
+
+// WatchRecursive watches a directory and all of
+// its subdirectories, including subdirectories
+// created after the watch was added.
+func WatchRecursive() WatchOption {
+	return func(o *watchOptions) {
+		o.recursive = true
+	}
+}
+
+// WatchInclude restricts the changes of a watched
+// directory which trigger a reload, to paths matching
+// any of the globs. Globs are matched against paths
+// relative to the watched directory using forward
+// slashes, and `**` matches any number of directories,
+// e.g. `**/*.conf`.
+func WatchInclude(globs ...string) WatchOption {
+	return func(o *watchOptions) {
+		o.include = append(o.include, globs...)
+	}
+}
+
+// WatchExclude ignores changes of a watched directory
+// to paths matching any of the globs, using the same
+// syntax as WatchInclude.
+func WatchExclude(globs ...string) WatchOption {
+	return func(o *watchOptions) {
+		o.exclude = append(o.exclude, globs...)
+	}
+}
+
+// watchTarget is a path added to a fileWatcher.
+type watchTarget struct {
+	path string
+	opts watchOptions
+
+	// real is the resolved path of the target,
+	// or empty if the target doesn't exist.
+	real string
+	dir  bool
+}
+
+// fileWatcher watches files through their parent
+// directories, which makes it possible to follow
+// files being replaced by a rename, and symlinks
+// being swapped such as the `..data` symlink of a
+// Kubernetes ConfigMap volume. Paths which don't
+// exist yet are watched through their nearest
+// existing parent directory until they appear.
+type fileWatcher struct {
+	tracked
+	w     *fsnotify.Watcher
+	fn    func(trigger string)
+	errfn func(error)
+
+	m       sync.Mutex
+	targets map[string]*watchTarget
+}
+
+func newFileWatcher(errfn func(error)) (*fileWatcher, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	fw := &fileWatcher{
+		w:       w,
+		errfn:   errfn,
+		targets: make(map[string]*watchTarget),
+	}
+
+	return fw, nil
+}
+
+// Start implements Trigger, and delivers events
+// until ctx is done or the watcher is closed.
+func (fw *fileWatcher) Start(ctx context.Context, reload func(reason string)) error {
+	fw.fn = reload
+
+	fw.spawn(func() { fw.run(ctx) })
+
+	return nil
+}
+
+// Add watches a file or a directory. A file is
+// watched through its parent directory, and through
+// the directory of the file it resolves to when it is
+// a symlink. A directory triggers a reload for any
+// change of its entries.
+func (fw *fileWatcher) Add(path string, opts ...WatchOption) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	t := &watchTarget{path: path}
+	for _, opt := range opts {
+		opt(&t.opts)
+	}
+
+	fw.m.Lock()
+	defer fw.m.Unlock()
+
+	fw.targets[path] = t
+	_, err = fw.ensure(t)
+
+	return err
+}
+
+// Close stops watching all paths.
+func (fw *fileWatcher) Close() error {
+	return fw.w.Close()
+}
+
+func (fw *fileWatcher) run(ctx context.Context) {
+	defer func() { _ = fw.w.Close() }()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-fw.w.Events:
+			if !ok {
+				return
+			}
+			for _, trigger := range fw.triggers(event) {
+				fw.fn(trigger)
+			}
+		case err, ok := <-fw.w.Errors:
+			if !ok {
+				return
+			}
+			fw.errfn(err)
+		}
+	}
+}
+
+// ensure adds the watches needed to follow a target,
+// and returns true if the target exists. A missing
+// target is watched through its nearest existing
+// parent directory.
+func (fw *fileWatcher) ensure(t *watchTarget) (bool, error) {
+	fi, err := os.Stat(t.path)
+	if os.IsNotExist(err) {
+		t.real = ""
+		return false, fw.w.Add(nearestDir(t.path))
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if fi.IsDir() {
+		t.real = t.path
+		t.dir = true
+		_, err := fw.addDir(t.path, t.opts.recursive)
+		return true, err
+	}
+
+	real, err := filepath.EvalSymlinks(t.path)
+	if err != nil {
+		return false, err
+	}
+
+	t.real = real
+	t.dir = false
+	if err := fw.w.Add(filepath.Dir(t.path)); err != nil {
+		return true, err
+	}
+
+	if filepath.Dir(real) != filepath.Dir(t.path) {
+		return true, fw.w.Add(filepath.Dir(real))
+	}
+
+	return true, nil
+}
+
+// addDir watches a directory, and all subdirectories
+// if recursive is true. It returns every file found
+// in the subdirectories.
+func (fw *fileWatcher) addDir(dir string, recursive bool) ([]string, error) {
+	if !recursive {
+		return nil, fw.w.Add(dir)
+	}
+
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if !d.IsDir() {
+			files = append(files, p)
+			return nil
+		}
+
+		return fw.w.Add(p)
+	})
+
+	return files, err
+}
+
+// triggers returns the watched paths affected by
+// an event.
+func (fw *fileWatcher) triggers(event fsnotify.Event) []string {
+	fw.m.Lock()
+	defer fw.m.Unlock()
+
+	var triggers []string
+	for _, t := range fw.targets {
+		var trigger string
+		switch {
+		case t.real == "":
+			trigger = fw.missingEvent(t)
+		case t.dir:
+			trigger = fw.dirEvent(t, event)
+		default:
+			trigger = fw.fileEvent(t, event)
+		}
+
+		if trigger != "" {
+			triggers = append(triggers, trigger)
+		}
+	}
+
+	return triggers
+}
+
+// missingEvent checks whether a missing target has
+// appeared, or moves its watch closer to the target
+// when a parent directory has appeared.
+func (fw *fileWatcher) missingEvent(t *watchTarget) string {
+	ok, err := fw.ensure(t)
+	if err != nil {
+		fw.errfn(err)
+	}
+
+	if ok {
+		return t.path
+	}
+
+	return ""
+}
+
+// fileEvent checks whether an event affects a watched
+// file. A file is affected when the event concerns the
+// file itself or the file it resolves to, or when the
+// file now resolves to another file. A file which has
+// been removed is not affected until it appears again.
+func (fw *fileWatcher) fileEvent(t *watchTarget, event fsnotify.Event) string {
+	next, err := filepath.EvalSymlinks(t.path)
+	if err != nil {
+		if _, err := fw.ensure(t); err != nil {
+			fw.errfn(err)
+		}
+		return ""
+	}
+
+	if event.Name != t.path && event.Name != t.real && next == t.real {
+		return ""
+	}
+
+	if next != t.real {
+		if _, err := fw.ensure(t); err != nil {
+			fw.errfn(err)
+		}
+	}
+
+	return t.path
+}
+
+// dirEvent checks whether an event affects a watched
+// directory, and starts watching new subdirectories of
+// a recursively watched directory.
+func (fw *fileWatcher) dirEvent(t *watchTarget, event fsnotify.Event) string {
+	if event.Name == t.path {
+		if _, err := os.Stat(t.path); err != nil {
+			if _, err := fw.ensure(t); err != nil {
+				fw.errfn(err)
+			}
+		}
+		return t.path
+	}
+
+	rel, ok := t.rel(event.Name)
+	if !ok {
+		return ""
+	}
+
+	if fw.subdirEvent(t, event) {
+		return event.Name
+	}
+
+	if !t.opts.match(rel) {
+		return ""
+	}
+
+	return event.Name
+}
+
+// rel returns the path of name relative to a watched
+// directory, and false if name is outside of it or in
+// a subdirectory which isn't watched.
+func (t *watchTarget) rel(name string) (string, bool) {
+	rel, err := filepath.Rel(t.path, name)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	if strings.ContainsRune(rel, filepath.Separator) && !t.opts.recursive {
+		return "", false
+	}
+
+	return rel, true
+}
+
+// subdirEvent starts watching a directory created in a
+// recursively watched directory, and returns true if the
+// new directory contains any matching file.
+func (fw *fileWatcher) subdirEvent(t *watchTarget, event fsnotify.Event) bool {
+	if !t.opts.recursive || !event.Has(fsnotify.Create) {
+		return false
+	}
+
+	fi, err := os.Stat(event.Name)
+	if err != nil || !fi.IsDir() {
+		return false
+	}
+
+	files, err := fw.addDir(event.Name, true)
+	if err != nil {
+		fw.errfn(err)
+	}
+
+	for _, f := range files {
+		if rel, err := filepath.Rel(t.path, f); err == nil && t.opts.match(rel) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// match returns true if a path relative to a
+// watched directory is included and not excluded.
+func (o watchOptions) match(rel string) bool {
+	rel = filepath.ToSlash(rel)
+
+	included := len(o.include) == 0
+	for _, glob := range o.include {
+		if matchGlob(glob, rel) {
+			included = true
+			break
+		}
+	}
+
+	if !included {
+		return false
+	}
+
+	for _, glob := range o.exclude {
+		if matchGlob(glob, rel) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchGlob matches a slash separated name against a
+// glob using the syntax of path.Match, where a `**`
+// element matches any number of elements.
+func matchGlob(glob string, name string) bool {
+	return matchElems(strings.Split(glob, "/"), strings.Split(name, "/"))
+}
+
+func matchElems(glob []string, name []string) bool {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchElems(glob[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, _ := path.Match(glob[0], name[0]); !ok {
+			return false
+		}
+
+		glob = glob[1:]
+		name = name[1:]
+	}
+
+	return len(name) == 0
+}
+
+// nearestDir returns the nearest existing
+// directory of a path, or the path itself if
+// it is an existing directory.
+func nearestDir(p string) string {
+	for {
+		if fi, err := os.Stat(p); err == nil && fi.IsDir() {
+			return p
+		}
+
+		parent := filepath.Dir(p)
+		if parent == p {
+			return p
+		}
+		p = parent
+	}
+}
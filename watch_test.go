@@ -0,0 +1,323 @@
+package binder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTriggers struct {
+	m        sync.Mutex
+	triggers []string
+}
+
+func (f *fakeTriggers) fn(trigger string) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.triggers = append(f.triggers, trigger)
+}
+
+func (f *fakeTriggers) errfn(err error) {}
+
+func (f *fakeTriggers) count() int {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	return len(f.triggers)
+}
+
+func (f *fakeTriggers) waitFor(t *testing.T, n int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		return f.count() >= n
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func (f *fakeTriggers) fileWatcher(t *testing.T) *fileWatcher {
+	t.Helper()
+
+	fw, err := newFileWatcher(f.errfn)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	assert.NoError(t, fw.Start(ctx, f.fn))
+
+	return fw
+}
+
+func (f *fakeTriggers) pollWatcher(t *testing.T, interval time.Duration) *pollWatcher {
+	t.Helper()
+
+	pw := newPollWatcher(interval, f.errfn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	assert.NoError(t, pw.Start(ctx, f.fn))
+
+	return pw
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	assert.NoError(t, err)
+}
+
+func Test_FileWatcher_Write(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	writeFile(t, fp, "key=1")
+
+	f := &fakeTriggers{}
+	fw := f.fileWatcher(t)
+
+	assert.NoError(t, fw.Add(fp))
+
+	writeFile(t, fp, "key=2")
+	f.waitFor(t, 1)
+
+	f.m.Lock()
+	defer f.m.Unlock()
+	assert.Equal(t, fp, f.triggers[0])
+}
+
+func Test_FileWatcher_Unrelated(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	writeFile(t, fp, "key=1")
+
+	f := &fakeTriggers{}
+	fw := f.fileWatcher(t)
+
+	assert.NoError(t, fw.Add(fp))
+
+	writeFile(t, filepath.Join(dir, "other.conf"), "key=2")
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Zero(t, f.count())
+}
+
+func Test_FileWatcher_AtomicRename(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	writeFile(t, fp, "key=1")
+
+	f := &fakeTriggers{}
+	fw := f.fileWatcher(t)
+
+	assert.NoError(t, fw.Add(fp))
+
+	tmp := filepath.Join(dir, ".values.conf.swp")
+	writeFile(t, tmp, "key=2")
+	assert.NoError(t, os.Rename(tmp, fp))
+	f.waitFor(t, 1)
+
+	n := f.count()
+	writeFile(t, fp, "key=3")
+	f.waitFor(t, n+1)
+}
+
+func Test_FileWatcher_RemoveAndCreate(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	writeFile(t, fp, "key=1")
+
+	f := &fakeTriggers{}
+	fw := f.fileWatcher(t)
+
+	assert.NoError(t, fw.Add(fp))
+
+	assert.NoError(t, os.Remove(fp))
+	time.Sleep(100 * time.Millisecond)
+	assert.Zero(t, f.count())
+
+	writeFile(t, fp, "key=2")
+	f.waitFor(t, 1)
+}
+
+// configMapVolume simulates the layout which the Kubernetes
+// atomic writer uses for ConfigMap volumes, where every file
+// is a symlink into the `..data` symlink, which in turn points
+// at a timestamped directory.
+type configMapVolume struct {
+	t   *testing.T
+	dir string
+	ts  string
+}
+
+func newConfigMapVolume(t *testing.T, files map[string]string) *configMapVolume {
+	v := &configMapVolume{t: t, dir: t.TempDir()}
+	v.update(files)
+
+	for name := range files {
+		err := os.Symlink(filepath.Join("..data", name), filepath.Join(v.dir, name))
+		assert.NoError(t, err)
+	}
+
+	return v
+}
+
+func (v *configMapVolume) update(files map[string]string) {
+	ts := "..2026_10_18_" + time.Now().Format("150405.000000000")
+	assert.NoError(v.t, os.Mkdir(filepath.Join(v.dir, ts), 0o700))
+
+	for name, content := range files {
+		writeFile(v.t, filepath.Join(v.dir, ts, name), content)
+	}
+
+	tmp := filepath.Join(v.dir, "..data_tmp")
+	assert.NoError(v.t, os.Symlink(ts, tmp))
+	assert.NoError(v.t, os.Rename(tmp, filepath.Join(v.dir, "..data")))
+
+	if v.ts != "" {
+		assert.NoError(v.t, os.RemoveAll(filepath.Join(v.dir, v.ts)))
+	}
+	v.ts = ts
+}
+
+func Test_FileWatcher_ConfigMap_File(t *testing.T) {
+	v := newConfigMapVolume(t, map[string]string{"values.conf": "key=1"})
+	fp := filepath.Join(v.dir, "values.conf")
+
+	f := &fakeTriggers{}
+	fw := f.fileWatcher(t)
+
+	assert.NoError(t, fw.Add(fp))
+
+	v.update(map[string]string{"values.conf": "key=2"})
+	f.waitFor(t, 1)
+
+	b, err := os.ReadFile(fp)
+	assert.NoError(t, err)
+	assert.Equal(t, "key=2", string(b))
+
+	n := f.count()
+	v.update(map[string]string{"values.conf": "key=3"})
+	f.waitFor(t, n+1)
+}
+
+func Test_FileWatcher_ConfigMap_Volume(t *testing.T) {
+	v := newConfigMapVolume(t, map[string]string{"key": "1"})
+
+	f := &fakeTriggers{}
+	fw := f.fileWatcher(t)
+
+	assert.NoError(t, fw.Add(v.dir))
+
+	v.update(map[string]string{"key": "2"})
+	f.waitFor(t, 1)
+}
+
+func Test_Watch_Reload(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "values.conf")
+	writeFile(t, fp, "binder_key=value1")
+
+	c := New(
+		WithFile(fp, "="),
+		WithWatch(fp))
+	defer c.Close()
+
+	var b fakeBinder3
+	c.Bind(&b)
+	assert.Equal(t, "value1", b.ValueField)
+
+	w := BindAtomic[fakeBinder3](c)
+
+	tmp := filepath.Join(dir, "values.conf.tmp")
+	writeFile(t, tmp, "binder_key=value2")
+	assert.NoError(t, os.Rename(tmp, fp))
+
+	assert.Eventually(t, func() bool {
+		return w.Load().ValueField == "value2"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func Test_FileWatcher_Missing(t *testing.T) {
+	dir := t.TempDir()
+	fp := filepath.Join(dir, "conf", "nested", "values.conf")
+
+	f := &fakeTriggers{}
+	fw := f.fileWatcher(t)
+
+	assert.NoError(t, fw.Add(fp))
+
+	assert.NoError(t, os.MkdirAll(filepath.Dir(fp), 0o700))
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, f.count())
+
+	writeFile(t, fp, "key=1")
+	f.waitFor(t, 1)
+
+	n := f.count()
+	writeFile(t, fp, "key=2")
+	f.waitFor(t, n+1)
+}
+
+func Test_FileWatcher_Recursive(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "a"), 0o700))
+
+	f := &fakeTriggers{}
+	fw := f.fileWatcher(t)
+
+	err := fw.Add(dir,
+		WatchRecursive(),
+		WatchInclude("**/*.conf"),
+		WatchExclude("**/ignored.conf"))
+	assert.NoError(t, err)
+
+	writeFile(t, filepath.Join(dir, "a", "other.txt"), "x")
+	writeFile(t, filepath.Join(dir, "a", "ignored.conf"), "x")
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, f.count())
+
+	writeFile(t, filepath.Join(dir, "a", "values.conf"), "key=1")
+	f.waitFor(t, 1)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "b", "c"), 0o700))
+	time.Sleep(50 * time.Millisecond)
+
+	n := f.count()
+	writeFile(t, filepath.Join(dir, "b", "c", "values.conf"), "key=1")
+	f.waitFor(t, n+1)
+}
+
+func Test_FileWatcher_NonRecursive(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "a"), 0o700))
+
+	f := &fakeTriggers{}
+	fw := f.fileWatcher(t)
+
+	assert.NoError(t, fw.Add(dir))
+
+	writeFile(t, filepath.Join(dir, "a", "values.conf"), "key=1")
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, f.count())
+
+	writeFile(t, filepath.Join(dir, "values.conf"), "key=1")
+	f.waitFor(t, 1)
+}
+
+func Test_MatchGlob(t *testing.T) {
+	assert.True(t, matchGlob("*.conf", "a.conf"))
+	assert.False(t, matchGlob("*.conf", "a/b.conf"))
+	assert.True(t, matchGlob("**/*.conf", "a.conf"))
+	assert.True(t, matchGlob("**/*.conf", "a/b/c.conf"))
+	assert.True(t, matchGlob("conf.d/**", "conf.d/a/b"))
+	assert.False(t, matchGlob("conf.d/**", "other/a"))
+	assert.True(t, matchGlob("a/**/c.conf", "a/c.conf"))
+	assert.False(t, matchGlob("a/**/c.conf", "a/b/d.conf"))
+}
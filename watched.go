@@ -0,0 +1,78 @@
+package binder
+
+import (
+	"errors"
+	"reflect"
+	"sync/atomic"
+)
+
+// Watched holds an immutable snapshot of a bound
+// type T, which is replaced on every reload. It is
+// safe to Load a snapshot while a reload is running.
+type Watched[T any] struct {
+	prefix string
+	p      atomic.Pointer[T]
+}
+
+type snapshot interface {
+	reload(c *core) ([]string, []error)
+}
+
+// BindAtomic binds configuration values to a fresh
+// instance of the struct type T on every reload, and
+// publishes it through the returned Watched. Instances
+// are never written to after being published, which
+// makes it possible to read configuration from any
+// goroutine while reloads are running.
+func BindAtomic[T any](c *Config) *Watched[T] {
+	w := &Watched[T]{prefix: c.prefix}
+
+	var zero T
+	if reflect.TypeOf(&zero).Elem().Kind() != reflect.Struct {
+		c.errs(errors.New("cannot bind to non-struct"))
+		w.p.Store(&zero)
+		return w
+	}
+
+	c.current()
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	_, errs := w.reload(c.core)
+	c.report(errs)
+	c.snapshots = append(c.snapshots, w)
+
+	return w
+}
+
+// Load returns the latest snapshot. The returned
+// instance must not be modified.
+func (w *Watched[T]) Load() *T {
+	return w.p.Load()
+}
+
+// reload binds and publishes a fresh snapshot, where
+// fields tagged `reload:"false"` keep their values from
+// the previous snapshot. It returns the keys of such
+// fields which would have changed, and the errors of
+// fields which couldn't be bound.
+func (w *Watched[T]) reload(c *core) ([]string, []error) {
+	values := c.cache
+	if w.prefix != "" {
+		values = values.sub(w.prefix, c.mask)
+	}
+
+	var next T
+	d := newDecoder(values, c.mask, c.tags)
+	d.prefix = w.prefix
+	if prev := w.p.Load(); prev != nil {
+		d.reload = true
+		keepFrozen(reflect.ValueOf(&next).Elem(), reflect.ValueOf(prev).Elem())
+	}
+	d.bindStruct(reflect.ValueOf(&next).Elem())
+
+	w.p.Store(&next)
+
+	return d.restart, d.errs
+}
@@ -0,0 +1,125 @@
+package binder
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BindAtomic(t *testing.T) {
+	p := &fakeRebindParser{"value1"}
+	c := New(WithParser(p))
+
+	w := BindAtomic[fakeBinder3](c)
+	first := w.Load()
+	assert.Equal(t, "value1", first.ValueField)
+
+	p.value = "value2"
+	c.apply("test")
+
+	assert.Equal(t, "value2", w.Load().ValueField)
+	assert.Equal(t, "value1", first.ValueField)
+}
+
+func Test_BindAtomic_Sub(t *testing.T) {
+	m := make(map[string]interface{})
+	m["plugins.x.binder_key"] = "x"
+
+	c := New(WithParser(newFakeParser(m)))
+
+	w := BindAtomic[fakeBinder3](c.Sub("plugins.x"))
+
+	assert.Equal(t, "x", w.Load().ValueField)
+}
+
+func Test_BindAtomic_NonStruct(t *testing.T) {
+	c := New()
+
+	w := BindAtomic[int](c)
+
+	assert.Equal(t, 0, *w.Load())
+	assert.Error(t, <-c.Errors())
+}
+
+type lockedRebindParser struct {
+	m     sync.Mutex
+	value string
+}
+
+func (p *lockedRebindParser) Parse() (map[string]interface{}, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return map[string]interface{}{"binder_key": p.value}, nil
+}
+
+func (p *lockedRebindParser) set(value string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.value = value
+}
+
+func Test_BindAtomic_ReloadUnderLoad(t *testing.T) {
+	p := &lockedRebindParser{value: "a"}
+	c := New(WithParser(p))
+
+	w := BindAtomic[fakeBinder3](c)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					v := w.Load().ValueField
+					if v != "a" && v != "b" {
+						t.Errorf("unexpected value %q", v)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			p.set("b")
+		} else {
+			p.set("a")
+		}
+		c.apply("test")
+	}
+
+	close(done)
+	wg.Wait()
+}
+
+type fakeFrozenSnapshot struct {
+	Port int    `config:"port" reload:"false"`
+	Host string `config:"host"`
+}
+
+func Test_BindAtomic_ReloadFalse(t *testing.T) {
+	p := &fakeMultiParser{map[string]interface{}{
+		"port": 8080,
+		"host": "a",
+	}}
+	c := New(WithParser(p))
+	defer c.Close()
+
+	w := BindAtomic[fakeFrozenSnapshot](c)
+
+	p.m["port"] = 9090
+	p.m["host"] = "b"
+	c.apply("test")
+
+	assert.Equal(t, &fakeFrozenSnapshot{8080, "b"}, w.Load())
+	assert.Equal(t, &RestartRequiredError{[]string{"port"}}, <-c.Errors())
+}